@@ -0,0 +1,77 @@
+package audio
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+)
+
+// BandEnergy measures the amplitude of the frequencies between a low and a high cutoff,
+// over a window of the most recent samples. A full scale sine wave in the band reads about 1.
+type BandEnergy struct {
+	Level
+	sampleRate int
+	low        float64
+	high       float64
+	window     []float64
+	samples    []float32
+	buf        []complex128
+}
+
+// NewBandEnergy creates a new BandEnergy, the window size must be a power of two
+func NewBandEnergy(sampleRate int, low float64, high float64, windowSize int) (*BandEnergy, error) {
+	if !isPowerOfTwo(windowSize) {
+		return nil, errors.New("window size must be a power of two")
+	}
+	if low < 0 || high <= low || high > float64(sampleRate)/2 {
+		return nil, errors.New("band must be within zero and half the sample rate")
+	}
+	b := &BandEnergy{
+		sampleRate: sampleRate,
+		low:        low,
+		high:       high,
+		window:     make([]float64, windowSize),
+		samples:    make([]float32, 0, windowSize),
+		buf:        make([]complex128, windowSize),
+	}
+	// Hann window
+	for i := range b.window {
+		b.window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(windowSize))
+	}
+	return b, nil
+}
+
+// Process feeds the samples in, the value is updated each time the window fills up
+func (b *BandEnergy) Process(samples []float32) {
+	for _, s := range samples {
+		b.samples = append(b.samples, s)
+		if len(b.samples) == len(b.window) {
+			b.Set(float32(b.measure()))
+			b.samples = b.samples[:0]
+		}
+	}
+}
+
+// measure returns the amplitude of the band in the current window
+func (b *BandEnergy) measure() float64 {
+	var gain float64
+	for i := range b.samples {
+		b.buf[i] = complex(float64(b.samples[i])*b.window[i], 0)
+		gain += b.window[i]
+	}
+	fft(b.buf)
+	// sum the power of the bins in the band, only the first half of the bins are unique
+	binWidth := float64(b.sampleRate) / float64(len(b.buf))
+	var power float64
+	for k := 0; k <= len(b.buf)/2; k++ {
+		f := float64(k) * binWidth
+		if f < b.low || f > b.high {
+			continue
+		}
+		m := cmplx.Abs(b.buf[k])
+		power += m * m
+	}
+	// a sine of amplitude A has a peak of A*gain/2, and the Hann window spreads its power over
+	// bins that sum to 1.5 times the peak power
+	return math.Sqrt(power/1.5) / (gain / 2)
+}
@@ -0,0 +1,41 @@
+package audio
+
+import "testing"
+
+func TestNewBandEnergyErrors(t *testing.T) {
+	tests := []struct {
+		low, high float64
+		size      int
+	}{
+		{20, 200, 1000},
+		{200, 20, 1024},
+		{20, 30000, 1024},
+	}
+	for _, tc := range tests {
+		if _, err := NewBandEnergy(44100, tc.low, tc.high, tc.size); err == nil {
+			t.Errorf("Wanted error for %v", tc)
+		}
+	}
+}
+
+func TestBandEnergy(t *testing.T) {
+	tests := []struct {
+		freq     float64
+		min, max float32
+	}{
+		// in band, full scale
+		{100, 0.9, 1.1},
+		// out of band
+		{5000, 0, 0.05},
+	}
+	for _, tc := range tests {
+		b, err := NewBandEnergy(44100, 20, 250, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Process(sine(tc.freq, 1, 44100, 2048))
+		if got := b.Value(); got < tc.min || got > tc.max {
+			t.Errorf("Wanted %v to %v, got: %v", tc.min, tc.max, got)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBeatDetector(t *testing.T) {
+	const rate = 8000
+	d, err := NewBeatDetector(rate, 256, time.Second, 1.5, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// two seconds of quiet noise floor with a loud burst every half second
+	samples := sine(220, 0.05, rate, 2*rate)
+	for start := rate; start < 2*rate; start += rate / 2 {
+		copy(samples[start:], sine(220, 1, rate, 512))
+	}
+	d.Process(samples[:rate+256])
+	if got := d.Value(); got != 1 {
+		t.Errorf("Wanted %v, got: %v", 1, got)
+	}
+	d.Process(samples[rate+256:])
+	if got := d.Beats(); got != 2 {
+		t.Errorf("Wanted %v, got: %v", 2, got)
+	}
+	// the pulse decays in silence
+	d.Process(make([]float32, rate/2))
+	if got := d.Value(); got > 0.01 {
+		t.Errorf("Wanted at most %v, got: %v", 0.01, got)
+	}
+}
+
+func TestNewBeatDetectorErrors(t *testing.T) {
+	if _, err := NewBeatDetector(8000, 0, time.Second, 1.5, time.Second); err == nil {
+		t.Errorf("Wanted error for zero block size")
+	}
+	if _, err := NewBeatDetector(8000, 8000, time.Second, 1.5, time.Second); err == nil {
+		t.Errorf("Wanted error for short history")
+	}
+}
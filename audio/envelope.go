@@ -0,0 +1,45 @@
+package audio
+
+import (
+	"math"
+	"time"
+)
+
+// EnvelopeFollower tracks the loudness of a stream of samples in the range [-1, 1].
+// It rises with the attack time and falls with the release time.
+type EnvelopeFollower struct {
+	Level
+	attack   float64
+	release  float64
+	envelope float64
+}
+
+// NewEnvelopeFollower creates a new EnvelopeFollower for samples at the sample rate
+func NewEnvelopeFollower(sampleRate int, attack time.Duration, release time.Duration) *EnvelopeFollower {
+	return &EnvelopeFollower{
+		attack:  smoothing(sampleRate, attack),
+		release: smoothing(sampleRate, release),
+	}
+}
+
+// smoothing returns the per sample coefficient that covers about 63% of a change in the given time
+func smoothing(sampleRate int, d time.Duration) float64 {
+	samples := d.Seconds() * float64(sampleRate)
+	if samples <= 0 {
+		return 1
+	}
+	return 1 - math.Exp(-1/samples)
+}
+
+// Process feeds the samples to the follower and updates its value
+func (e *EnvelopeFollower) Process(samples []float32) {
+	for _, s := range samples {
+		x := math.Abs(float64(s))
+		coef := e.release
+		if x > e.envelope {
+			coef = e.attack
+		}
+		e.envelope += coef * (x - e.envelope)
+	}
+	e.Set(float32(e.envelope))
+}
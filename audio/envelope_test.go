@@ -0,0 +1,30 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// sine returns n samples of a sine wave
+func sine(freq float64, amplitude float64, sampleRate int, n int) []float32 {
+	s := make([]float32, n)
+	for i := range s {
+		s[i] = float32(amplitude * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate)))
+	}
+	return s
+}
+
+func TestEnvelopeFollower(t *testing.T) {
+	e := NewEnvelopeFollower(44100, time.Millisecond, 100*time.Millisecond)
+	// loud signal raises the envelope
+	e.Process(sine(440, 1, 44100, 4410))
+	if got := e.Value(); got < 0.6 {
+		t.Errorf("Wanted at least %v, got: %v", 0.6, got)
+	}
+	// silence lets it fall
+	e.Process(make([]float32, 44100))
+	if got := e.Value(); got > 0.01 {
+		t.Errorf("Wanted at most %v, got: %v", 0.01, got)
+	}
+}
@@ -0,0 +1,41 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestFFT(t *testing.T) {
+	// compare with a direct DFT
+	x := []complex128{1, 2, 3, 4, 0, -1, 0.5, 2}
+	want := make([]complex128, len(x))
+	for k := range want {
+		for n, v := range x {
+			want[k] += v * cmplx.Exp(complex(0, -2*math.Pi*float64(k*n)/float64(len(x))))
+		}
+	}
+	fft(x)
+	for k := range x {
+		if cmplx.Abs(x[k]-want[k]) > 1e-9 {
+			t.Errorf("Wanted %v, got: %v", want[k], x[k])
+		}
+	}
+}
+
+func TestIsPowerOfTwo(t *testing.T) {
+	tests := []struct {
+		n    int
+		want bool
+	}{
+		{0, false},
+		{1, true},
+		{6, false},
+		{1024, true},
+	}
+	for _, tc := range tests {
+		if got := isPowerOfTwo(tc.n); got != tc.want {
+			t.Errorf("Wanted %v, got: %v", tc.want, got)
+		}
+	}
+}
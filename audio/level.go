@@ -0,0 +1,28 @@
+package audio
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Level holds a value in the range [0, 1] that one goroutine sets and others read.
+// It implements transfunc.Signal.
+type Level struct {
+	bits uint32
+}
+
+// Set stores the value, clamped to the range [0, 1]
+func (l *Level) Set(value float32) {
+	if value < 0 || value != value {
+		value = 0
+	}
+	if value > 1 {
+		value = 1
+	}
+	atomic.StoreUint32(&l.bits, math.Float32bits(value))
+}
+
+// Value returns the stored value
+func (l *Level) Value() float32 {
+	return math.Float32frombits(atomic.LoadUint32(&l.bits))
+}
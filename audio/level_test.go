@@ -0,0 +1,21 @@
+package audio
+
+import "testing"
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		set  float32
+		want float32
+	}{
+		{0.5, 0.5},
+		{-1, 0},
+		{2, 1},
+	}
+	var l Level
+	for _, tc := range tests {
+		l.Set(tc.set)
+		if got := l.Value(); got != tc.want {
+			t.Errorf("Wanted %v, got: %v", tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// wavStreamSize is the data chunk size written by recorders that don't know the length up front
+const wavStreamSize = math.MaxUint32
+
+// ReadWAV reads a 16 bit PCM WAV file and returns its samples mixed down to mono in the range [-1, 1].
+// A data chunk with a size of 0xFFFFFFFF, as written by streaming recorders, is read to the end of the file.
+func ReadWAV(r io.Reader) (samples []float32, sampleRate int, err error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, 0, err
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, 0, errors.New("not a WAV file")
+	}
+	var channels, bitsPerSample int
+	for {
+		// each chunk has a four letter id and a little endian length
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			if err == io.EOF {
+				return nil, 0, errors.New("WAV file has no data chunk")
+			}
+			return nil, 0, err
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:]))
+		switch id {
+		case "fmt ":
+			body, err := readChunk(r, size+size%2)
+			if err != nil {
+				return nil, 0, err
+			}
+			if size < 16 {
+				return nil, 0, errors.New("WAV format chunk too short")
+			}
+			if format := binary.LittleEndian.Uint16(body[0:]); format != 1 {
+				return nil, 0, fmt.Errorf("unsupported WAV format %d, only PCM is supported", format)
+			}
+			channels = int(binary.LittleEndian.Uint16(body[2:]))
+			sampleRate = int(binary.LittleEndian.Uint32(body[4:]))
+			bitsPerSample = int(binary.LittleEndian.Uint16(body[14:]))
+			if bitsPerSample != 16 || channels < 1 {
+				return nil, 0, fmt.Errorf("unsupported WAV layout: %d channels of %d bits", channels, bitsPerSample)
+			}
+		case "data":
+			if channels == 0 {
+				return nil, 0, errors.New("WAV data chunk before format chunk")
+			}
+			var body []byte
+			if size == wavStreamSize {
+				body, err = io.ReadAll(r)
+			} else {
+				body, err = readChunk(r, size)
+			}
+			if err != nil {
+				return nil, 0, err
+			}
+			return mixDown(body, channels), sampleRate, nil
+		default:
+			// skip chunks we don't use without holding them in memory
+			if _, err := io.CopyN(io.Discard, r, size+size%2); err != nil {
+				return nil, 0, unexpectedEOF(err)
+			}
+		}
+	}
+}
+
+// readChunk reads the chunk body, the buffer grows as the data arrives rather than trusting the size
+// in the header up front
+func readChunk(r io.Reader, size int64) ([]byte, error) {
+	var body bytes.Buffer
+	if _, err := io.CopyN(&body, r, size); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return body.Bytes(), nil
+}
+
+// unexpectedEOF reports a chunk that ends early the same way io.ReadFull does
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// mixDown converts interleaved 16 bit samples into mono float samples
+func mixDown(data []byte, channels int) []float32 {
+	frameSize := 2 * channels
+	samples := make([]float32, len(data)/frameSize)
+	for i := range samples {
+		var sum float32
+		for c := 0; c < channels; c++ {
+			v := int16(binary.LittleEndian.Uint16(data[i*frameSize+2*c:]))
+			sum += float32(v) / -math.MinInt16
+		}
+		samples[i] = sum / float32(channels)
+	}
+	return samples
+}
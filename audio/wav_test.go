@@ -0,0 +1,101 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+)
+
+// wav builds a 16 bit PCM WAV file from interleaved samples
+func wav(sampleRate int, channels int, samples []int16) []byte {
+	var data bytes.Buffer
+	binary.Write(&data, binary.LittleEndian, samples)
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(4+8+16+8+8+data.Len()))
+	buf.WriteString("WAVE")
+	// an unknown chunk that should be skipped
+	buf.WriteString("LIST")
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+	buf.WriteString("fmt ")
+	for _, v := range []interface{}{
+		uint32(16), uint16(1), uint16(channels), uint32(sampleRate),
+		uint32(sampleRate * channels * 2), uint16(channels * 2), uint16(16),
+	} {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(data.Len()))
+	buf.Write(data.Bytes())
+	return buf.Bytes()
+}
+
+func TestReadWAV(t *testing.T) {
+	file := wav(22050, 2, []int16{16384, 16384, -32768, 0, 0, 32767})
+	samples, rate, err := ReadWAV(bytes.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 22050 {
+		t.Errorf("Wanted %v, got: %v", 22050, rate)
+	}
+	want := []float32{0.5, -0.5, 32767.0 / 65536}
+	if len(samples) != len(want) {
+		t.Fatalf("Wanted %v, got: %v", want, samples)
+	}
+	for i := range want {
+		if samples[i] != want[i] {
+			t.Errorf("Wanted %v, got: %v", want[i], samples[i])
+		}
+	}
+}
+
+func TestReadWAVErrors(t *testing.T) {
+	tests := [][]byte{
+		[]byte("RIFF\x00\x00\x00\x00AVI "),
+		[]byte("RIFF\x00\x00\x00\x00WAVE"),
+		[]byte("RIFF"),
+	}
+	for _, tc := range tests {
+		if _, _, err := ReadWAV(bytes.NewReader(tc)); err == nil {
+			t.Errorf("Wanted error for %q", tc)
+		}
+	}
+}
+
+// withDataSize replaces the data chunk size in a WAV file built by wav
+func withDataSize(file []byte, numSamples int, size uint32) []byte {
+	binary.LittleEndian.PutUint32(file[len(file)-2*numSamples-4:], size)
+	return file
+}
+
+func TestReadWAVChunkSizes(t *testing.T) {
+	tests := []struct {
+		name        string
+		size        uint32
+		wantSamples int
+		wantErr     error
+	}{
+		{"streamed", 0xffffffff, 3, nil},
+		{"truncated", 0xfffffff0, 0, io.ErrUnexpectedEOF},
+		{"slightly short", 14, 0, io.ErrUnexpectedEOF},
+	}
+
+	for _, test := range tests {
+		file := withDataSize(wav(8000, 1, []int16{1, 2, 3}), 3, test.size)
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		samples, _, err := ReadWAV(bytes.NewReader(file))
+		runtime.ReadMemStats(&after)
+		if !errors.Is(err, test.wantErr) || len(samples) != test.wantSamples {
+			t.Errorf("%v: Wanted %v samples and error %v, got: %v and %v", test.name, test.wantSamples, test.wantErr, len(samples), err)
+		}
+		// the size in the header must not be allocated up front
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+			t.Errorf("%v: Wanted less than 1MB allocated, got: %v", test.name, allocated)
+		}
+	}
+}
@@ -4,26 +4,39 @@ import (
 	"fmt"
 	imageColor "image/color"
 	"math"
+	"sync"
 
 	"github.com/gazek/color-blender/color"
 	"github.com/gazek/color-blender/transfunc"
 )
 
-// Blender modifies a color over time according to the provided color, brightness and white level transition functions
+// Blender modifies a color over time according to the provided color, brightness and white level transition functions.
+// It is safe for concurrent use, so one goroutine can render while another appends functions or advances the step.
 type Blender struct {
+	mu              sync.RWMutex
 	colorFuncs      transfunc.ColorFuncSlice
 	brightnessFuncs transfunc.BrightnessFuncSlice
 	whiteLevelFuncs transfunc.WhiteLevelFuncSlice
 	step            int
+	// masterDim is how far the master brightness is below full, so the zero value is full brightness
+	masterDim    uint8
+	dimmingCurve DimmingCurve
 }
 
+// DefaultBrightness is the brightness used when no brightness functions have been appended
+const DefaultBrightness = math.MaxUint8
+
 // ResetStep sets the step position to zero
 func (b *Blender) ResetStep() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	b.step = 0
 }
 
 // AdvanceStep changes the current step position by the numSteps amount
 func (b *Blender) AdvanceStep(numSteps int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	// get a common period
 	period := b.getPeriod()
 	// handle period of zero
@@ -41,98 +54,227 @@ func (b *Blender) AdvanceStep(numSteps int) {
 
 // AppendColorFunc appends the ColorFunc to the ColorFuncSlice
 func (b *Blender) AppendColorFunc(f transfunc.ColorFunc) {
+	// calculate the transition distance up front so GetColor never writes to the func
+	if f.TransType == transfunc.OneAtATime && f.TransDist <= 0 {
+		_, f.TransDist = b._oneAtATimeColorTransition(f.Color1, f.Color2, 4*math.MaxUint8)
+	}
+	if f.TransType == transfunc.Decomposed && f.TransDist <= 0 {
+		d1, d2 := b.decomposeEndpoints(f.Color1, f.Color2)
+		_, f.TransDist = b._oneAtATimeColorTransition(d1.Base, d2.Base, 4*math.MaxUint8)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	b.colorFuncs.AppendFunc(&f)
 }
 
 // AppendBrightnessFunc appends the ColorFunc to the ColorFuncSlice
 func (b *Blender) AppendBrightnessFunc(f transfunc.BrightnessFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	b.brightnessFuncs.AppendFunc(&f)
 }
 
 // AppendWhiteLevelFunc appends the ColorFunc to the ColorFuncSlice
 func (b *Blender) AppendWhiteLevelFunc(f transfunc.WhiteLevelFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	b.whiteLevelFuncs.AppendFunc(&f)
 }
 
-// GetColor calculates the color for the current step position
+// SetMasterBrightness sets a brightness that scales the output of the brightness functions.
+// It is part of the program, when a Player drives the blender use the Player's brightness as the dimmer.
+func (b *Blender) SetMasterBrightness(brightness uint8) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.masterDim = math.MaxUint8 - brightness
+}
+
+// GetMasterBrightness returns the master brightness
+func (b *Blender) GetMasterBrightness() uint8 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return math.MaxUint8 - b.masterDim
+}
+
+// SetDimmingCurve sets the curve the master brightness is passed through, nil means LinearCurve
+func (b *Blender) SetDimmingCurve(curve DimmingCurve) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dimmingCurve = curve
+}
+
+// GetStep returns the current step position
+func (b *Blender) GetStep() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.step
+}
+
+// GetPeriod returns the number of steps after which the blender repeats itself
+func (b *Blender) GetPeriod() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.getPeriod()
+}
+
+// Validate checks that every function can be evaluated, a blender that passes never panics in GetColor
+func (b *Blender) Validate() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.validate()
+}
+
+// validate checks each of the function slices
+func (b *Blender) validate() error {
+	if err := b.colorFuncs.Validate(); err != nil {
+		return fmt.Errorf("color %w", err)
+	}
+	if err := b.brightnessFuncs.Validate(); err != nil {
+		return fmt.Errorf("brightness %w", err)
+	}
+	if err := b.whiteLevelFuncs.Validate(); err != nil {
+		return fmt.Errorf("white level %w", err)
+	}
+	return nil
+}
+
+// GetColor calculates the color for the current step position, it panics if the blender is not valid
 func (b *Blender) GetColor() *color.Color {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return mustColor(b.getColorAtStep(b.step))
+}
+
+// GetColorE calculates the color for the current step position, returning an error instead of panicking
+// if the blender is not valid
+func (b *Blender) GetColorE() (*color.Color, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	return b.getColorAtStep(b.step)
+}
+
+// GetColorAtStep calculates the color for any step position without changing the current step position,
+// it panics if the blender is not valid
+func (b *Blender) GetColorAtStep(step int) *color.Color {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return mustColor(b.getColorAtStep(b.wrapStep(step)))
+}
+
+// GetColorAtStepE calculates the color for any step position, returning an error instead of panicking
+// if the blender is not valid
+func (b *Blender) GetColorAtStepE(step int) (*color.Color, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	return b.getColorAtStep(b.wrapStep(step))
+}
+
+// wrapStep wraps the step into the period, negative steps count back from the end
+func (b *Blender) wrapStep(step int) int {
+	if period := b.getPeriod(); period > 0 {
+		step = ((step % period) + period) % period
+	}
+	return step
+}
+
+// mustColor panics if there is an error, it keeps the behavior of the methods that don't return errors
+func mustColor(c *color.Color, err error) *color.Color {
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// getColorAtStep calculates the color for the given step position
+func (b *Blender) getColorAtStep(step int) (*color.Color, error) {
 	// create a new Color object to hold the result
 	result := &color.Color{}
-	// get the color func value
-	cfv, cf := b.colorFuncs.GetFuncValue(b.step)
-	// get the base color resulting from the func value
-	result.SetColor(b.getTransitionColor(cf, cfv))
+	// get the color func value, without color funcs the base color is black
+	cfv, cf := b.colorFuncs.GetFuncValue(step)
+	if cf != nil {
+		// get the base color resulting from the func value
+		base, err := b.getTransitionColor(cf, cfv)
+		if err != nil {
+			return nil, err
+		}
+		result.SetColor(base)
+	}
 	// get the brightness func value
-	bfv, ok := b.brightnessFuncs.GetFuncValue(b.step)
-	// apply the brightness to the base color
-	if ok {
-		result.SetBrightness(bfv)
+	bfv, ok := b.brightnessFuncs.GetFuncValue(step)
+	// without brightness funcs the color is fully visible rather than left at the alpha of the color func
+	if !ok {
+		bfv = DefaultBrightness
 	}
+	// apply the brightness to the base color
+	result.SetBrightness(b.applyMasterBrightness(bfv))
 	// get the white level func value
-	wlfv, ok := b.whiteLevelFuncs.GetFuncValue(b.step)
-	// apply the white level to the base color
+	wlfv, ok := b.whiteLevelFuncs.GetFuncValue(step)
+	// apply the white level to the base color, blending toward the func's white point if it has one
 	if ok {
-		result.SetWhiteLevel(wlfv)
+		if whitePoint, hasWhitePoint := b.whiteLevelFuncs.GetWhitePoint(step); hasWhitePoint {
+			result.SetWhiteLevelWithWhitePoint(wlfv, whitePoint)
+		} else {
+			result.SetWhiteLevel(wlfv)
+		}
 	}
 	// return the resulting color
-	return result
+	return result, nil
+}
+
+// applyMasterBrightness scales the brightness by the master brightness after passing it through the dimming curve
+func (b *Blender) applyMasterBrightness(brightness uint8) uint8 {
+	return Dim(brightness, math.MaxUint8-b.masterDim, b.dimmingCurve)
 }
 
+// getPeriod returns the least common multiple of the func slice periods, so funcs with equal periods
+// keep the same period when combined
 func (b *Blender) getPeriod() int {
-	// I don't want to write an LCM function
-	// so I'll just do it the easy way
-	isZero := true
-	period := 1
-	// multiply all the periods
-	if b.colorFuncs.GetPeriod() > 0 {
-		period *= b.colorFuncs.GetPeriod()
-		isZero = false
-	}
-	if b.brightnessFuncs.GetPeriod() > 0 {
-		period *= b.brightnessFuncs.GetPeriod()
-		isZero = false
-	}
-	if b.whiteLevelFuncs.GetPeriod() > 0 {
-		period *= b.whiteLevelFuncs.GetPeriod()
-		isZero = false
-	}
-	// return the result
-	if isZero {
-		return 0
-	}
-	return period
+	return transfunc.CommonPeriod(b.colorFuncs.GetPeriod(), b.brightnessFuncs.GetPeriod(), b.whiteLevelFuncs.GetPeriod())
 }
 
 // getColorTransTypeFunc selects the transition function
-func (b *Blender) getColorTransTypeFunc(transType transfunc.TransType) func(colorFunc *transfunc.ColorFunc, transPercent float32) imageColor.RGBA {
+func (b *Blender) getColorTransTypeFunc(transType transfunc.TransType) (func(colorFunc *transfunc.ColorFunc, transPercent float32) imageColor.RGBA, error) {
 	switch transType {
 	case transfunc.OneAtATime:
-		return b.oneAtATimeColorTransition
+		return b.oneAtATimeColorTransition, nil
 	case transfunc.AllAtOnce:
-		return b.allAtOnceColorTransition
+		return b.allAtOnceColorTransition, nil
 	case transfunc.ToWhite:
-		return b.whiteColorTransition
+		return b.whiteColorTransition, nil
 	case transfunc.ToBlack:
-		return b.blackColorTransition
+		return b.blackColorTransition, nil
+	case transfunc.Decomposed:
+		return b.decomposedColorTransition, nil
+	case transfunc.Temperature:
+		return b.temperatureColorTransition, nil
 	default:
-		panic(fmt.Sprintf("Invalid color transition type: %v", transType))
+		return nil, fmt.Errorf("%w: %v", transfunc.ErrInvalidTransType, transType)
 	}
 }
 
 // getTransitionColor retreives the transition function and gets the transition color
-func (b *Blender) getTransitionColor(colorFunc *transfunc.ColorFunc, transPercent float32) imageColor.RGBA {
-	transTypeFunc := b.getColorTransTypeFunc(colorFunc.TransType)
-	color := transTypeFunc(colorFunc, transPercent)
-	return color
+func (b *Blender) getTransitionColor(colorFunc *transfunc.ColorFunc, transPercent float32) (imageColor.RGBA, error) {
+	transTypeFunc, err := b.getColorTransTypeFunc(colorFunc.TransType)
+	if err != nil {
+		return imageColor.RGBA{}, err
+	}
+	return transTypeFunc(colorFunc, transPercent), nil
 }
 
 // oneAtATimeColorTransition transitions between colors by changing only one component value at a time
 func (b *Blender) oneAtATimeColorTransition(colorFunc *transfunc.ColorFunc, transPercent float32) imageColor.RGBA {
-	// get the full transition distance if we don't already have it
-	if colorFunc.TransDist <= 0 {
-		_, colorFunc.TransDist = b._oneAtATimeColorTransition(colorFunc.Color1, colorFunc.Color2, 4*math.MaxUint8)
+	// get the full transition distance if it wasn't calculated when the func was appended
+	transDist := colorFunc.TransDist
+	if transDist <= 0 {
+		_, transDist = b._oneAtATimeColorTransition(colorFunc.Color1, colorFunc.Color2, 4*math.MaxUint8)
 	}
-	maxDist := int(math.Round(float64(transPercent * float32(colorFunc.TransDist))))
+	maxDist := int(math.Round(float64(transPercent * float32(transDist))))
 	color, _ := b._oneAtATimeColorTransition(colorFunc.Color1, colorFunc.Color2, maxDist)
 	return color
 }
@@ -144,19 +286,19 @@ func (b *Blender) _oneAtATimeColorTransition(color1 imageColor.RGBA, color2 imag
 	// initialize the RGBA to color1
 	result := color.NewColor(color1)
 	// get the components by dmominance
-	c1DomPtrs, c1DomNames := color.NewColor(color1).GetColorDominance(&color1)
-	c2DomPtrs, c2DomNames := color.NewColor(color2).GetColorDominance(&color2)
+	c1DomPtrs, c1DomComps := color.NewColor(color1).GetColorDominance(&color1)
+	c2DomPtrs, c2DomComps := color.NewColor(color2).GetColorDominance(&color2)
 	// avoid backtracking across the same path
 	if *c1DomPtrs[1] != *c2DomPtrs[0] {
 		// result[c1.d1] => 0
-		dist += b.setComponentWithConstraint(result, c1DomNames[1], 0, maxDist-dist)
+		dist += b.setComponentWithConstraint(result, c1DomComps[1], 0, maxDist-dist)
 	}
 	// result[c2.d0] => 255
-	dist += b.setComponentWithConstraint(result, c2DomNames[0], 255, maxDist-dist)
+	dist += b.setComponentWithConstraint(result, c2DomComps[0], 255, maxDist-dist)
 	// result[c2.d2] => 0
-	dist += b.setComponentWithConstraint(result, c2DomNames[2], 0, maxDist-dist)
+	dist += b.setComponentWithConstraint(result, c2DomComps[2], 0, maxDist-dist)
 	// result[c2.d1] => c2[c2.d1]
-	dist += b.setComponentWithConstraint(result, c2DomNames[1], *c2DomPtrs[1], maxDist-dist)
+	dist += b.setComponentWithConstraint(result, c2DomComps[1], *c2DomPtrs[1], maxDist-dist)
 	// return the color
 	return result.GetColor(), dist
 }
@@ -164,12 +306,83 @@ func (b *Blender) _oneAtATimeColorTransition(color1 imageColor.RGBA, color2 imag
 // allAtOnceColorTransition transitions between colors by changing all component values at once, moving them directly toward the target values
 func (b *Blender) allAtOnceColorTransition(cf *transfunc.ColorFunc, transPercent float32) imageColor.RGBA {
 	return imageColor.RGBA{
-		R: cf.Color1.R + uint8(float32((cf.Color2.R-cf.Color1.R))*transPercent),
-		G: cf.Color1.G + uint8(float32((cf.Color2.G-cf.Color1.G))*transPercent),
-		B: cf.Color1.B + uint8(float32((cf.Color2.B-cf.Color1.B))*transPercent),
+		R: b.allAtOnceComponent(cf.Color1.R, cf.Color2.R, transPercent),
+		G: b.allAtOnceComponent(cf.Color1.G, cf.Color2.G, transPercent),
+		B: b.allAtOnceComponent(cf.Color1.B, cf.Color2.B, transPercent),
 	}
 }
 
+// allAtOnceComponent moves a single component value toward the target value, the difference
+// is signed so components can move down as well as up
+func (b *Blender) allAtOnceComponent(value1 uint8, value2 uint8, transPercent float32) uint8 {
+	change := int(float32(int(value2)-int(value1)) * transPercent)
+	return uint8(int(value1) + change)
+}
+
+// decomposedColorTransition transitions between colors by splitting them into base color, white level and value.
+// The base color changes one component at a time, so it stays fully saturated, while the white level and value
+// move directly toward the target values.
+func (b *Blender) decomposedColorTransition(colorFunc *transfunc.ColorFunc, transPercent float32) imageColor.RGBA {
+	d1, d2 := b.decomposeEndpoints(colorFunc.Color1, colorFunc.Color2)
+	// get the full base color transition distance if it wasn't calculated when the func was appended
+	transDist := colorFunc.TransDist
+	if transDist <= 0 {
+		_, transDist = b._oneAtATimeColorTransition(d1.Base, d2.Base, 4*math.MaxUint8)
+	}
+	maxDist := int(math.Round(float64(transPercent * float32(transDist))))
+	base, _ := b._oneAtATimeColorTransition(d1.Base, d2.Base, maxDist)
+	return color.Compose(color.Decomposed{
+		Base:       base,
+		WhiteLevel: b.allAtOnceComponent(d1.WhiteLevel, d2.WhiteLevel, transPercent),
+		Value:      b.allAtOnceComponent(d1.Value, d2.Value, transPercent),
+	})
+}
+
+// decomposeEndpoints decomposes both colors of a transition, black or true white takes the base color
+// of the other color so the hue doesn't drift through the arbitrary base color
+func (b *Blender) decomposeEndpoints(color1 imageColor.RGBA, color2 imageColor.RGBA) (d1 color.Decomposed, d2 color.Decomposed) {
+	d1, d2 = color.Decompose(color1), color.Decompose(color2)
+	if d1.IsAchromatic() {
+		d1.Base = d2.Base
+	}
+	if d2.IsAchromatic() {
+		d2.Base = d1.Base
+	}
+	return d1, d2
+}
+
+// temperatureColorTransition transitions between the color temperatures of the colors along the blackbody locus.
+// The temperature moves evenly in mireds, which looks more even than kelvin, and the value moves directly.
+func (b *Blender) temperatureColorTransition(colorFunc *transfunc.ColorFunc, transPercent float32) imageColor.RGBA {
+	// the end points are exact
+	if transPercent <= 0 {
+		return imageColor.RGBA{R: colorFunc.Color1.R, G: colorFunc.Color1.G, B: colorFunc.Color1.B}
+	}
+	if transPercent >= 1 {
+		return imageColor.RGBA{R: colorFunc.Color2.R, G: colorFunc.Color2.G, B: colorFunc.Color2.B}
+	}
+	d1, d2 := color.Decompose(colorFunc.Color1), color.Decompose(colorFunc.Color2)
+	// black has no temperature so it takes the temperature of the other color
+	k1, k2 := color.CCT(colorFunc.Color1), color.CCT(colorFunc.Color2)
+	if d1.Value == 0 {
+		k1 = k2
+	}
+	if d2.Value == 0 {
+		k2 = k1
+	}
+	if d1.Value == 0 && d2.Value == 0 {
+		return imageColor.RGBA{}
+	}
+	k1 = math.Max(color.MinKelvin, math.Min(color.MaxKelvin, k1))
+	k2 = math.Max(color.MinKelvin, math.Min(color.MaxKelvin, k2))
+	// interpolate the mireds
+	m1, m2 := 1e6/k1, 1e6/k2
+	d := color.Decompose(color.Kelvin(1e6 / (m1 + (m2-m1)*float64(transPercent))))
+	d.Value = b.allAtOnceComponent(d1.Value, d2.Value, transPercent)
+	d.Brightness = 0
+	return color.Compose(d)
+}
+
 // whiteColorTransition similar to allAtOnceColorTransition but transitions to white before transitioning to the target values
 func (b *Blender) whiteColorTransition(colorFunc *transfunc.ColorFunc, transPercent float32) imageColor.RGBA {
 	return imageColor.RGBA{}
@@ -180,26 +393,29 @@ func (b *Blender) blackColorTransition(colorFunc *transfunc.ColorFunc, transPerc
 	return imageColor.RGBA{}
 }
 
-// setComponentWithConstraint sets a color component value but restricts the amount the value can deviate from its current value
-func (b *Blender) setComponentWithConstraint(color *color.Color, compName string, value uint8, maxDist int) (distTraveled int) {
+// setComponentWithConstraint sets an RGB component value but restricts the amount the value can deviate from its current value
+func (b *Blender) setComponentWithConstraint(c *color.Color, comp color.Component, value uint8, maxDist int) (distTraveled int) {
 	// check the base case
 	if maxDist <= 0 {
 		return 0
 	}
-	// calculate the component change
-	compValue := color.GetComponentValue(compName)
+	// calculate the component change, the dominance components are always R, G or B
+	compValue, err := c.GetComponentValue(comp)
+	if err != nil {
+		panic(err)
+	}
 	change := int(value) - int(compValue)
 	// get the distance
 	dist := int(math.Abs(float64(change)))
 	// set the component value
 	if dist <= maxDist {
-		color.SetComponentValue(compName, value)
+		c.SetComponentValue(comp, value)
 		distTraveled = dist
 	} else {
 		if change > 0 {
-			color.SetComponentValue(compName, color.GetComponentValue(compName)+uint8(maxDist))
+			c.SetComponentValue(comp, compValue+uint8(maxDist))
 		} else {
-			color.SetComponentValue(compName, color.GetComponentValue(compName)-uint8(maxDist))
+			c.SetComponentValue(comp, compValue-uint8(maxDist))
 		}
 		distTraveled = maxDist
 	}
@@ -1,7 +1,9 @@
 package blender
 
 import (
+	"errors"
 	ic "image/color"
+	"sync"
 	"testing"
 
 	"github.com/gazek/color-blender/color"
@@ -11,17 +13,17 @@ import (
 func TestSetComponentWithConstraint(t *testing.T) {
 	tests := []struct {
 		color     ic.RGBA
-		component string
+		component color.Component
 		value     uint8
 		maxDist   int
 		want      ic.RGBA
 	}{
-		{ic.RGBA{R: 0, G: 0, B: 0}, "R", 200, 255, ic.RGBA{R: 200, G: 0, B: 0}},
-		{ic.RGBA{R: 0, G: 0, B: 0}, "G", 200, 255, ic.RGBA{R: 0, G: 200, B: 0}},
-		{ic.RGBA{R: 0, G: 0, B: 0}, "B", 200, 255, ic.RGBA{R: 0, G: 0, B: 200}},
-		{ic.RGBA{R: 0, G: 0, B: 0}, "B", 200, 0, ic.RGBA{R: 0, G: 0, B: 0}},
-		{ic.RGBA{R: 0, G: 0, B: 0}, "B", 200, 75, ic.RGBA{R: 0, G: 0, B: 75}},
-		{ic.RGBA{R: 0, G: 0, B: 200}, "B", 0, 75, ic.RGBA{R: 0, G: 0, B: 125}},
+		{ic.RGBA{R: 0, G: 0, B: 0}, color.Red, 200, 255, ic.RGBA{R: 200, G: 0, B: 0}},
+		{ic.RGBA{R: 0, G: 0, B: 0}, color.Green, 200, 255, ic.RGBA{R: 0, G: 200, B: 0}},
+		{ic.RGBA{R: 0, G: 0, B: 0}, color.Blue, 200, 255, ic.RGBA{R: 0, G: 0, B: 200}},
+		{ic.RGBA{R: 0, G: 0, B: 0}, color.Blue, 200, 0, ic.RGBA{R: 0, G: 0, B: 0}},
+		{ic.RGBA{R: 0, G: 0, B: 0}, color.Blue, 200, 75, ic.RGBA{R: 0, G: 0, B: 75}},
+		{ic.RGBA{R: 0, G: 0, B: 200}, color.Blue, 0, 75, ic.RGBA{R: 0, G: 0, B: 125}},
 	}
 
 	b := Blender{}
@@ -83,7 +85,10 @@ func TestGetTransitionColor(t *testing.T) {
 			Color2:    test.color2,
 			TransType: test.transType,
 		}
-		color := b.getTransitionColor(cf, test.percent)
+		color, err := b.getTransitionColor(cf, test.percent)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
 		if color != test.wantColor {
 			t.Errorf("Wanted %v, got: %v", test.wantColor, color)
 		}
@@ -99,6 +104,9 @@ func TestGetPeriod(t *testing.T) {
 	}{
 		{0, 0, 0, 0},
 		{1, 2, 3, 6},
+		{4, 6, 0, 12},
+		{1440, 1440, 1440, 1440},
+		{0, 5, 0, 5},
 	}
 
 	for _, test := range tests {
@@ -134,6 +142,9 @@ func TestAdvanceStepAndResetStep(t *testing.T) {
 		{1, 2, 3, -3, 7, 4},
 		{1, 2, 3, 4, -9999, 0},
 		{0, 0, 0, 0, 10, 0},
+		// funcs with equal periods wrap together
+		{4, 4, 0, 3, 1, 0},
+		{4, 6, 0, 10, 3, 1},
 	}
 
 	for _, test := range tests {
@@ -297,3 +308,268 @@ func TestGetGetColor(t *testing.T) {
 		}
 	}
 }
+
+func TestGetColorAtStep(t *testing.T) {
+	tests := []struct {
+		step int
+		want uint8
+	}{
+		{0, 0},
+		{3, 150},
+		{7, 100},
+		{-1, 200},
+	}
+
+	b := Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{R: 250}, transfunc.AllAtOnce, func(x float32) float32 { return x }, 5, []float32{0, 1}))
+	b.AdvanceStep(1)
+	for _, test := range tests {
+		if result := b.GetColorAtStep(test.step).GetColor().R; result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+	// the current step should not change
+	if b.GetStep() != 1 {
+		t.Errorf("Wanted: %v, found: %v", 1, b.GetStep())
+	}
+	if b.GetPeriod() != 5 {
+		t.Errorf("Wanted: %v, found: %v", 5, b.GetPeriod())
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	// run with go test -race to check for data races
+	b := &Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{R: 255}, ic.RGBA{B: 255}, transfunc.OneAtATime, func(x float32) float32 { return x }, 10, []float32{0, 1}))
+	b.AppendBrightnessFunc(transfunc.NewBrightnessFunc(func(x float32) float32 { return 1 }, 10, nil))
+	var wg sync.WaitGroup
+	// several render goroutines
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				b.GetColor()
+				b.GetColorAtStep(i)
+				b.GetStep()
+			}
+		}()
+	}
+	// one control goroutine
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			b.AdvanceStep(1)
+			if i%50 == 0 {
+				b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{G: 255}, ic.RGBA{R: 255}, transfunc.OneAtATime, func(x float32) float32 { return x }, 10, []float32{0, 1}))
+				b.AppendWhiteLevelFunc(transfunc.NewWhiteLevelFunc(func(x float32) float32 { return 0 }, 10, nil))
+			}
+		}
+		b.ResetStep()
+	}()
+	wg.Wait()
+	if b.GetStep() != 0 {
+		t.Errorf("Wanted: %v, found: %v", 0, b.GetStep())
+	}
+}
+
+func TestAppendColorFuncTransDist(t *testing.T) {
+	b := Blender{}
+	cf := transfunc.NewColorFunc(ic.RGBA{R: 255}, ic.RGBA{G: 45, B: 255}, transfunc.OneAtATime, func(x float32) float32 { return x }, 1, nil)
+	b.AppendColorFunc(cf)
+	_, stored := b.colorFuncs.GetFuncValue(0)
+	if stored.TransDist != 555 {
+		t.Errorf("Wanted: %v, found: %v", 555, stored.TransDist)
+	}
+}
+
+func TestAllAtOnceColorTransition(t *testing.T) {
+	tests := []struct {
+		color1  ic.RGBA
+		color2  ic.RGBA
+		percent float32
+		want    ic.RGBA
+	}{
+		{ic.RGBA{R: 0, G: 0, B: 0}, ic.RGBA{R: 200, G: 100, B: 50}, 0.5, ic.RGBA{R: 100, G: 50, B: 25}},
+		{ic.RGBA{R: 255, G: 0, B: 0}, ic.RGBA{R: 0, G: 0, B: 255}, 0.5, ic.RGBA{R: 128, G: 0, B: 127}},
+		{ic.RGBA{R: 255, G: 10, B: 0}, ic.RGBA{R: 0, G: 10, B: 255}, 1, ic.RGBA{R: 0, G: 10, B: 255}},
+		{ic.RGBA{R: 255, G: 10, B: 0}, ic.RGBA{R: 0, G: 10, B: 255}, 0, ic.RGBA{R: 255, G: 10, B: 0}},
+	}
+
+	b := &Blender{}
+	for _, test := range tests {
+		cf := transfunc.ColorFunc{Color1: test.color1, Color2: test.color2}
+		if result := b.allAtOnceColorTransition(&cf, test.percent); result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
+
+func TestMasterBrightness(t *testing.T) {
+	tests := []struct {
+		name             string
+		brightnessFunc   bool
+		masterBrightness uint8
+		curve            DimmingCurve
+		want             uint8
+	}{
+		{"default", false, 255, nil, 255},
+		{"brightness func", true, 255, nil, 127},
+		{"linear master", false, 128, nil, 128},
+		{"linear master and func", true, 128, nil, 64},
+		{"cie master", false, 128, CIELightnessCurve, 47},
+		{"gamma master", false, 128, GammaCurve(2), 64},
+		{"off", true, 0, CIELightnessCurve, 0},
+	}
+
+	for _, test := range tests {
+		b := Blender{}
+		b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{R: 255}, ic.RGBA{R: 255}, transfunc.AllAtOnce, func(x float32) float32 { return 0 }, 1, nil))
+		if test.brightnessFunc {
+			b.AppendBrightnessFunc(transfunc.NewBrightnessFunc(func(x float32) float32 { return 0.5 }, 1, nil))
+		}
+		b.SetMasterBrightness(test.masterBrightness)
+		b.SetDimmingCurve(test.curve)
+		if b.GetMasterBrightness() != test.masterBrightness {
+			t.Errorf("%v: Wanted %v, got: %v", test.name, test.masterBrightness, b.GetMasterBrightness())
+		}
+		if result := b.GetColor().GetColor().A; result != test.want {
+			t.Errorf("%v: Wanted %v, got: %v", test.name, test.want, result)
+		}
+		// the program should keep the master brightness
+		if result := b.Compile().GetColorAtStep(0).GetColor().A; result != test.want {
+			t.Errorf("%v program: Wanted %v, got: %v", test.name, test.want, result)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	f := func(x float32) float32 { return x }
+	tests := []struct {
+		colorFunc      transfunc.ColorFunc
+		brightnessFunc transfunc.BrightnessFunc
+		want           error
+	}{
+		{transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{R: 255}, transfunc.AllAtOnce, f, 4, nil), transfunc.NewBrightnessFunc(f, 4, nil), nil},
+		{transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{R: 255}, transfunc.TransType(9), f, 4, nil), transfunc.NewBrightnessFunc(f, 4, nil), transfunc.ErrInvalidTransType},
+		{transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{R: 255}, transfunc.AllAtOnce, f, 0, nil), transfunc.NewBrightnessFunc(f, 4, nil), transfunc.ErrInvalidPeriod},
+		{transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{R: 255}, transfunc.AllAtOnce, f, 4, nil), transfunc.NewBrightnessFunc(nil, 4, nil), transfunc.ErrNilFunction},
+	}
+
+	for _, test := range tests {
+		b := &Blender{}
+		b.AppendColorFunc(test.colorFunc)
+		b.AppendBrightnessFunc(test.brightnessFunc)
+		if err := b.Validate(); !errors.Is(err, test.want) {
+			t.Errorf("Wanted %v, got: %v", test.want, err)
+		}
+		c, err := b.GetColorE()
+		if !errors.Is(err, test.want) {
+			t.Errorf("Wanted %v, got: %v", test.want, err)
+		}
+		if (c == nil) != (test.want != nil) {
+			t.Errorf("Wanted a color only without an error, got: %v", c)
+		}
+		if _, err := b.GetColorAtStepE(1); !errors.Is(err, test.want) {
+			t.Errorf("Wanted %v, got: %v", test.want, err)
+		}
+	}
+}
+
+func TestGetColorEmpty(t *testing.T) {
+	b := &Blender{}
+	want := ic.RGBA{A: DefaultBrightness}
+	c, err := b.GetColorE()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if c.GetColor() != want {
+		t.Errorf("Wanted %v, got: %v", want, c.GetColor())
+	}
+	if got := b.GetColor().GetColor(); got != want {
+		t.Errorf("Wanted %v, got: %v", want, got)
+	}
+}
+
+func TestGetColorPanicsWhenInvalid(t *testing.T) {
+	b := &Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{}, transfunc.TransType(9), func(x float32) float32 { return x }, 4, nil))
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Wanted a panic for an invalid transition type")
+		}
+	}()
+	b.GetColor()
+}
+
+func TestDecomposedColorTransition(t *testing.T) {
+	tests := []struct {
+		color1    ic.RGBA
+		color2    ic.RGBA
+		percent   float32
+		wantColor ic.RGBA
+	}{
+		{ic.RGBA{R: 255}, ic.RGBA{B: 255}, 0.25, ic.RGBA{R: 255, B: 128}},
+		{ic.RGBA{R: 255}, ic.RGBA{B: 255}, 0.75, ic.RGBA{R: 127, B: 255}},
+		// the hue of white is taken from the other color
+		{ic.RGBA{R: 255}, ic.RGBA{R: 255, G: 255, B: 255}, 0.5, ic.RGBA{R: 255, G: 127, B: 127}},
+		// the hue of black is taken from the other color
+		{ic.RGBA{}, ic.RGBA{G: 128}, 0.5, ic.RGBA{G: 64}},
+		{ic.RGBA{R: 200, G: 100, B: 50}, ic.RGBA{R: 20, G: 40, B: 80}, 0, ic.RGBA{R: 200, G: 100, B: 50}},
+		{ic.RGBA{R: 200, G: 100, B: 50}, ic.RGBA{R: 20, G: 40, B: 80}, 1, ic.RGBA{R: 20, G: 40, B: 80}},
+	}
+
+	for _, test := range tests {
+		b := &Blender{}
+		b.AppendColorFunc(transfunc.NewColorFunc(test.color1, test.color2, transfunc.Decomposed, func(x float32) float32 { return x }, 4, nil))
+		_, cf := b.colorFuncs.GetFuncValue(0)
+		if color := b.decomposedColorTransition(cf, test.percent); color != test.wantColor {
+			t.Errorf("Wanted %v, got: %v", test.wantColor, color)
+		}
+	}
+}
+
+func TestAppendColorFuncDecomposedTransDist(t *testing.T) {
+	b := Blender{}
+	cf := transfunc.NewColorFunc(ic.RGBA{R: 128}, ic.RGBA{B: 20}, transfunc.Decomposed, func(x float32) float32 { return x }, 1, nil)
+	b.AppendColorFunc(cf)
+	_, stored := b.colorFuncs.GetFuncValue(0)
+	if stored.TransDist != 510 {
+		t.Errorf("Wanted: %v, found: %v", 510, stored.TransDist)
+	}
+}
+
+func TestTemperatureColorTransition(t *testing.T) {
+	tests := []struct {
+		color1    ic.RGBA
+		color2    ic.RGBA
+		percent   float32
+		wantColor ic.RGBA
+	}{
+		{color.Kelvin(2200), color.Kelvin(6500), 0, ic.RGBA{R: 255, G: 150, B: 47}},
+		{color.Kelvin(2200), color.Kelvin(6500), 0.5, ic.RGBA{R: 255, G: 192, B: 126}},
+		{color.Kelvin(2200), color.Kelvin(6500), 1, ic.RGBA{R: 255, G: 249, B: 254}},
+		// black takes the temperature of the other color
+		{ic.RGBA{}, color.Kelvin(2700), 0.5, ic.RGBA{R: 127, G: 86, B: 45}},
+		{ic.RGBA{}, ic.RGBA{}, 0.5, ic.RGBA{}},
+	}
+
+	b := &Blender{}
+	for _, test := range tests {
+		cf := transfunc.NewColorFunc(test.color1, test.color2, transfunc.Temperature, func(x float32) float32 { return x }, 4, nil)
+		if result := b.temperatureColorTransition(&cf, test.percent); result != test.wantColor {
+			t.Errorf("Wanted %v, got: %v", test.wantColor, result)
+		}
+	}
+}
+
+func TestWhitePointFunc(t *testing.T) {
+	b := &Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{R: 255}, ic.RGBA{R: 255}, transfunc.AllAtOnce, func(x float32) float32 { return x }, 4, nil))
+	b.AppendWhiteLevelFunc(transfunc.NewWhitePointFunc(color.Kelvin(2700), func(x float32) float32 { return 1 }, 4, nil))
+	want := ic.RGBA{R: 255, G: 173, B: 89, A: 255}
+	if result := b.GetColor().GetColor(); result != want {
+		t.Errorf("Wanted %v, got: %v", want, result)
+	}
+}
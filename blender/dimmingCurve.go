@@ -0,0 +1,51 @@
+package blender
+
+import "math"
+
+// DimmingCurve maps a perceived brightness level in the range [0, 1] to the output level in the range [0, 1]
+type DimmingCurve func(level float32) float32
+
+// LinearCurve passes the level through unchanged
+func LinearCurve(level float32) float32 {
+	return clampLevel(level)
+}
+
+// CIELightnessCurve treats the level as CIE L* lightness, so equal steps in level look like equal steps in brightness
+func CIELightnessCurve(level float32) float32 {
+	l := float64(clampLevel(level)) * 100
+	// the CIE 1976 L* to relative luminance conversion
+	if l <= 8 {
+		return float32(l / 903.3)
+	}
+	return float32(math.Pow((l+16)/116, 3))
+}
+
+// GammaCurve raises the level to the power of gamma
+func GammaCurve(gamma float32) DimmingCurve {
+	return func(level float32) float32 {
+		return float32(math.Pow(float64(clampLevel(level)), float64(gamma)))
+	}
+}
+
+// Dim scales the brightness by the master brightness after passing the master through the curve, nil means LinearCurve
+func Dim(brightness uint8, master uint8, curve DimmingCurve) uint8 {
+	if master == math.MaxUint8 {
+		return brightness
+	}
+	if curve == nil {
+		curve = LinearCurve
+	}
+	level := curve(float32(master) / math.MaxUint8)
+	return uint8(math.Round(float64(float32(brightness) * level)))
+}
+
+// clampLevel keeps the level in the range [0, 1]
+func clampLevel(level float32) float32 {
+	if level < 0 {
+		return 0
+	}
+	if level > 1 {
+		return 1
+	}
+	return level
+}
@@ -0,0 +1,53 @@
+package blender
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDimmingCurves(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve DimmingCurve
+		level float32
+		want  float32
+	}{
+		{"linear", LinearCurve, 0.5, 0.5},
+		{"linear low", LinearCurve, -1, 0},
+		{"linear high", LinearCurve, 2, 1},
+		{"cie zero", CIELightnessCurve, 0, 0},
+		{"cie dark", CIELightnessCurve, 0.05, 0.005535},
+		{"cie mid", CIELightnessCurve, 0.5, 0.184187},
+		{"cie full", CIELightnessCurve, 1, 1},
+		{"gamma", GammaCurve(2), 0.5, 0.25},
+		{"gamma full", GammaCurve(2.2), 1, 1},
+	}
+
+	for _, test := range tests {
+		if result := test.curve(test.level); math.Abs(float64(result-test.want)) > 1e-5 {
+			t.Errorf("%v: Wanted %v, got: %v", test.name, test.want, result)
+		}
+	}
+}
+
+func TestDim(t *testing.T) {
+	tests := []struct {
+		name       string
+		brightness uint8
+		master     uint8
+		curve      DimmingCurve
+		want       uint8
+	}{
+		{"full master", 200, 255, CIELightnessCurve, 200},
+		{"nil curve", 255, 128, nil, 128},
+		{"linear", 100, 51, LinearCurve, 20},
+		{"cie", 255, 128, CIELightnessCurve, 47},
+		{"off", 255, 0, GammaCurve(2), 0},
+	}
+
+	for _, test := range tests {
+		if result := Dim(test.brightness, test.master, test.curve); result != test.want {
+			t.Errorf("%v: Wanted %v, got: %v", test.name, test.want, result)
+		}
+	}
+}
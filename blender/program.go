@@ -0,0 +1,144 @@
+package blender
+
+import (
+	"fmt"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// Program is an immutable snapshot of a Blender's functions. It has no step position of its own,
+// so a single Program can be shared by any number of Cursors and goroutines.
+type Program struct {
+	blender *Blender
+}
+
+// Compile creates a Program from the functions and master brightness currently set on the blender.
+// Changes made to the blender afterwards do not affect the Program.
+func (b *Blender) Compile() *Program {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	// the transition distances were calculated on append, so the copied funcs are never written to
+	return &Program{
+		blender: &Blender{
+			colorFuncs:      b.colorFuncs.Clone(),
+			brightnessFuncs: b.brightnessFuncs.Clone(),
+			whiteLevelFuncs: b.whiteLevelFuncs.Clone(),
+			masterDim:       b.masterDim,
+			dimmingCurve:    b.dimmingCurve,
+		},
+	}
+}
+
+// GetPeriod returns the number of steps after which the program repeats itself
+func (p *Program) GetPeriod() int {
+	return p.blender.GetPeriod()
+}
+
+// GetColorAtStep calculates the color for any step position
+func (p *Program) GetColorAtStep(step int) *color.Color {
+	return p.blender.GetColorAtStep(step)
+}
+
+// GetColorAtStepE calculates the color for any step position, returning an error if the program is not valid
+func (p *Program) GetColorAtStepE(step int) (*color.Color, error) {
+	return p.blender.GetColorAtStepE(step)
+}
+
+// Validate checks that every function in the program can be evaluated
+func (p *Program) Validate() error {
+	return p.blender.Validate()
+}
+
+// Direction defines which way a Cursor moves through a Program
+type Direction int
+
+const (
+	// Forward moves the cursor toward later steps
+	Forward Direction = iota
+	// Reverse moves the cursor toward earlier steps
+	Reverse
+)
+
+func (d Direction) String() string {
+	if d < Forward || d > Reverse {
+		return fmt.Sprintf("Direction(%d)", int(d))
+	}
+	return [...]string{"Forward", "Reverse"}[d]
+}
+
+// Cursor is a lightweight playback position within a shared Program.
+// A Cursor is not safe for concurrent use, each goroutine should have its own.
+type Cursor struct {
+	program   *Program
+	step      int
+	offset    int
+	speed     int
+	direction Direction
+}
+
+// NewCursor creates a new Cursor at step zero that moves forward one step at a time
+func NewCursor(p *Program) *Cursor {
+	return &Cursor{
+		program:   p,
+		speed:     1,
+		direction: Forward,
+	}
+}
+
+// SetOffset sets the number of steps the cursor's color is shifted from its step position
+func (c *Cursor) SetOffset(offset int) {
+	c.offset = offset
+}
+
+// SetSpeed sets the number of program steps moved for each step the cursor is advanced
+func (c *Cursor) SetSpeed(speed int) {
+	c.speed = speed
+}
+
+// SetDirection sets which way the cursor moves when it is advanced
+func (c *Cursor) SetDirection(direction Direction) {
+	c.direction = direction
+}
+
+// ResetStep sets the step position to zero
+func (c *Cursor) ResetStep() {
+	c.step = 0
+}
+
+// AdvanceStep moves the step position by numSteps times the speed, in the cursor's direction
+func (c *Cursor) AdvanceStep(numSteps int) {
+	delta := numSteps * c.speed
+	if c.direction == Reverse {
+		delta = -delta
+	}
+	c.step = c.wrap(c.step + delta)
+}
+
+// GetStep returns the current step position
+func (c *Cursor) GetStep() int {
+	return c.step
+}
+
+// GetPeriod returns the number of steps after which the program repeats itself
+func (c *Cursor) GetPeriod() int {
+	return c.program.GetPeriod()
+}
+
+// GetColor calculates the color for the current step position and offset
+func (c *Cursor) GetColor() *color.Color {
+	return c.GetColorAtStep(c.step)
+}
+
+// GetColorAtStep calculates the color for any step position, shifted by the cursor's offset
+func (c *Cursor) GetColorAtStep(step int) *color.Color {
+	return c.program.GetColorAtStep(step + c.offset)
+}
+
+// wrap keeps the step within the program period, moving back past zero wraps to the end
+func (c *Cursor) wrap(step int) int {
+	period := c.program.GetPeriod()
+	if period == 0 {
+		return 0
+	}
+	return ((step % period) + period) % period
+}
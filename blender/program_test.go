@@ -0,0 +1,142 @@
+package blender
+
+import (
+	"errors"
+	ic "image/color"
+	"sync"
+	"testing"
+
+	"github.com/gazek/color-blender/transfunc"
+)
+
+// newRampBlender creates a blender whose red component is 50 times the step position
+func newRampBlender() *Blender {
+	b := &Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{R: 250}, transfunc.AllAtOnce, func(x float32) float32 { return x }, 5, []float32{0, 1}))
+	return b
+}
+
+func TestCompile(t *testing.T) {
+	b := newRampBlender()
+	p := b.Compile()
+	// appending to the blender should not change the program
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{G: 250}, transfunc.AllAtOnce, func(x float32) float32 { return x }, 5, []float32{0, 1}))
+	if p.GetPeriod() != 5 {
+		t.Errorf("Wanted: %v, found: %v", 5, p.GetPeriod())
+	}
+	if b.GetPeriod() != 10 {
+		t.Errorf("Wanted: %v, found: %v", 10, b.GetPeriod())
+	}
+	if result := p.GetColorAtStep(7).GetColor().R; result != 100 {
+		t.Errorf("Wanted: %v, found: %v", 100, result)
+	}
+}
+
+func TestProgramValidate(t *testing.T) {
+	p := newRampBlender().Compile()
+	if err := p.Validate(); err != nil {
+		t.Errorf("Wanted: %v, found: %v", nil, err)
+	}
+	if c, err := p.GetColorAtStepE(2); err != nil || c.GetColor().R != 100 {
+		t.Errorf("Wanted: %v, found: %v, %v", 100, c, err)
+	}
+	b := &Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{}, transfunc.AllAtOnce, nil, 5, nil))
+	if _, err := b.Compile().GetColorAtStepE(2); !errors.Is(err, transfunc.ErrNilFunction) {
+		t.Errorf("Wanted: %v, found: %v", transfunc.ErrNilFunction, err)
+	}
+}
+
+func TestCursorGetColorAtStep(t *testing.T) {
+	c := NewCursor(newRampBlender().Compile())
+	c.SetOffset(2)
+	if c.GetPeriod() != 5 {
+		t.Errorf("Wanted: %v, found: %v", 5, c.GetPeriod())
+	}
+	// the step position doesn't matter, only the offset
+	c.AdvanceStep(1)
+	if result := c.GetColorAtStep(1).GetColor().R; result != 150 {
+		t.Errorf("Wanted: %v, found: %v", 150, result)
+	}
+}
+
+func TestCursor(t *testing.T) {
+	tests := []struct {
+		speed     int
+		direction Direction
+		offset    int
+		advance   []int
+		wantStep  int
+		wantR     uint8
+	}{
+		{1, Forward, 0, []int{1}, 1, 50},
+		{2, Forward, 0, []int{1, 1}, 4, 200},
+		{2, Forward, 0, []int{3}, 1, 50},
+		{1, Reverse, 0, []int{1}, 4, 200},
+		{3, Reverse, 0, []int{1, 1}, 4, 200},
+		{1, Forward, 2, []int{1}, 1, 150},
+		{1, Forward, -2, []int{1}, 1, 200},
+	}
+
+	p := newRampBlender().Compile()
+	for _, test := range tests {
+		c := NewCursor(p)
+		c.SetSpeed(test.speed)
+		c.SetDirection(test.direction)
+		c.SetOffset(test.offset)
+		for _, n := range test.advance {
+			c.AdvanceStep(n)
+		}
+		if c.GetStep() != test.wantStep {
+			t.Errorf("Wanted step: %v, found: %v", test.wantStep, c.GetStep())
+		}
+		if result := c.GetColor().GetColor().R; result != test.wantR {
+			t.Errorf("Wanted: %v, found: %v", test.wantR, result)
+		}
+		c.ResetStep()
+		if c.GetStep() != 0 {
+			t.Errorf("Wanted: %v, found: %v", 0, c.GetStep())
+		}
+	}
+}
+
+func TestCursorEmptyProgram(t *testing.T) {
+	c := NewCursor((&Blender{}).Compile())
+	c.AdvanceStep(3)
+	if c.GetStep() != 0 {
+		t.Errorf("Wanted: %v, found: %v", 0, c.GetStep())
+	}
+}
+
+func TestSharedProgram(t *testing.T) {
+	// run with go test -race to check for data races
+	p := newRampBlender().Compile()
+	var wg sync.WaitGroup
+	results := make([]uint8, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := NewCursor(p)
+			c.SetOffset(i)
+			for s := 0; s < 100; s++ {
+				c.AdvanceStep(1)
+			}
+			results[i] = c.GetColor().GetColor().R
+		}(i)
+	}
+	wg.Wait()
+	// 100 steps is a whole number of periods, so only the offset matters
+	for i := range results {
+		want := uint8(50 * (i % 5))
+		if results[i] != want {
+			t.Errorf("Cursor %v: Wanted %v, found: %v", i, want, results[i])
+		}
+	}
+}
+
+func TestDirectionString(t *testing.T) {
+	if Forward.String() != "Forward" || Reverse.String() != "Reverse" || Direction(7).String() != "Direction(7)" {
+		t.Errorf("Unexpected direction names: %v, %v, %v", Forward, Reverse, Direction(7))
+	}
+}
@@ -0,0 +1,98 @@
+package blender
+
+import (
+	"errors"
+	"fmt"
+	imageColor "image/color"
+	"math"
+	"sort"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// StateCheck is how distinguishable a set of indicator states are for one type of color vision
+type StateCheck struct {
+	Deficiency color.Deficiency
+	// MinDistance is the smallest distance between a color of one state and a color of another
+	MinDistance float64
+	// StateA and StateB are the closest states, and ColorA and ColorB their closest colors as they're seen
+	StateA string
+	StateB string
+	ColorA imageColor.RGBA
+	ColorB imageColor.RGBA
+}
+
+// CheckStates walks the period of each state's blender and finds, for normal vision and each simulated color
+// vision deficiency, the closest pair of colors shown by two different states using the metric.
+// Brightness is left out since a dimmed color is still recognized by its hue, so a breathing state
+// is compared by the colors it breathes through.
+func CheckStates(states map[string]*Blender, metric color.Metric) ([]StateCheck, error) {
+	if len(states) < 2 {
+		return nil, errors.New("at least two states are needed")
+	}
+	// walk the states in name order so the results don't depend on the map order
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// collect the colors each state shows
+	colors := make([][]imageColor.RGBA, len(names))
+	for i, name := range names {
+		c, err := stateColors(states[name])
+		if err != nil {
+			return nil, fmt.Errorf("state %s: %w", name, err)
+		}
+		colors[i] = c
+	}
+	// find the closest pair for each type of color vision
+	results := make([]StateCheck, 0, len(color.Deficiencies))
+	for _, d := range color.Deficiencies {
+		check := StateCheck{Deficiency: d, MinDistance: math.Inf(1)}
+		seen := make([][]imageColor.RGBA, len(colors))
+		for i := range colors {
+			seen[i] = make([]imageColor.RGBA, len(colors[i]))
+			for j := range colors[i] {
+				seen[i][j] = color.Simulate(colors[i][j], d)
+			}
+		}
+		for a := range seen {
+			for b := a + 1; b < len(seen); b++ {
+				for _, ca := range seen[a] {
+					for _, cb := range seen[b] {
+						if dist := metric.Distance(ca, cb); dist < check.MinDistance {
+							check.MinDistance = dist
+							check.StateA, check.StateB = names[a], names[b]
+							check.ColorA, check.ColorB = ca, cb
+						}
+					}
+				}
+			}
+		}
+		results = append(results, check)
+	}
+	return results, nil
+}
+
+// stateColors returns the distinct colors a blender shows over its period, without their brightness
+func stateColors(b *Blender) ([]imageColor.RGBA, error) {
+	period := b.GetPeriod()
+	if period == 0 {
+		period = 1
+	}
+	var result []imageColor.RGBA
+	found := map[imageColor.RGBA]bool{}
+	for step := 0; step < period; step++ {
+		c, err := b.GetColorAtStepE(step)
+		if err != nil {
+			return nil, err
+		}
+		rgb := c.GetColor()
+		rgb.A = 0
+		if !found[rgb] {
+			found[rgb] = true
+			result = append(result, rgb)
+		}
+	}
+	return result, nil
+}
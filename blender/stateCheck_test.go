@@ -0,0 +1,91 @@
+package blender
+
+import (
+	ic "image/color"
+	"math"
+	"testing"
+
+	"github.com/gazek/color-blender/color"
+	"github.com/gazek/color-blender/transfunc"
+)
+
+// newStateBlender creates a blender that breathes in a single color
+func newStateBlender(c ic.RGBA) *Blender {
+	b := &Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(c, c, transfunc.AllAtOnce, func(x float32) float32 { return x }, 4, nil))
+	b.AppendBrightnessFunc(transfunc.NewBrightnessFunc(func(x float32) float32 { return x }, 4, []float32{0, 1}))
+	return b
+}
+
+func TestCheckStates(t *testing.T) {
+	states := map[string]*Blender{
+		"ok":    newStateBlender(ic.RGBA{G: 255}),
+		"error": newStateBlender(ic.RGBA{R: 255}),
+		"busy":  newStateBlender(ic.RGBA{B: 255}),
+	}
+	checks, err := CheckStates(states, color.CIEDE2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != len(color.Deficiencies) {
+		t.Fatalf("Wanted %v, got: %v", len(color.Deficiencies), len(checks))
+	}
+	for i, check := range checks {
+		if check.Deficiency != color.Deficiencies[i] {
+			t.Errorf("Wanted %v, got: %v", color.Deficiencies[i], check.Deficiency)
+		}
+	}
+	// the colors are far apart with normal vision, but red and green are close without red or green cones
+	if checks[0].MinDistance < 50 {
+		t.Errorf("Wanted at least %v, got: %v", 50, checks[0].MinDistance)
+	}
+	redGreen := color.CIEDE2000.Distance(ic.RGBA{R: 255}, ic.RGBA{G: 255})
+	for _, check := range checks[1:3] {
+		if check.StateA != "error" || check.StateB != "ok" {
+			t.Errorf("Wanted %v and %v, got: %v and %v for %v", "error", "ok", check.StateA, check.StateB, check.Deficiency)
+		}
+		if check.MinDistance > redGreen/2 {
+			t.Errorf("Wanted less than %v, got: %v for %v", redGreen/2, check.MinDistance, check.Deficiency)
+		}
+	}
+	// the brightness is left out
+	if checks[0].ColorA.A != 0 || checks[0].ColorB.A != 0 {
+		t.Errorf("Wanted no alpha, got: %v %v", checks[0].ColorA, checks[0].ColorB)
+	}
+}
+
+func TestCheckStatesTransition(t *testing.T) {
+	// a state that fades is as close as the nearest color it passes through
+	fade := &Blender{}
+	fade.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{R: 255}, ic.RGBA{B: 255}, transfunc.AllAtOnce, func(x float32) float32 { return x }, 3, []float32{0, 1}))
+	states := map[string]*Blender{
+		"fade":   fade,
+		"purple": newStateBlender(ic.RGBA{R: 255, B: 255}),
+	}
+	checks, err := CheckStates(states, color.EuclideanRGB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the fade passes through 170, 0, 85 and 85, 0, 170 which are equally near purple
+	if want := math.Hypot(85, 170); math.Abs(checks[0].MinDistance-want) > 1e-9 {
+		t.Errorf("Wanted %v, got: %v", want, checks[0].MinDistance)
+	}
+	if want := (ic.RGBA{R: 170, B: 85}); checks[0].ColorA != want {
+		t.Errorf("Wanted %v, got: %v", want, checks[0].ColorA)
+	}
+}
+
+func TestCheckStatesErrors(t *testing.T) {
+	valid := newStateBlender(ic.RGBA{G: 255})
+	invalid := &Blender{}
+	invalid.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{}, transfunc.AllAtOnce, nil, 4, nil))
+	tests := []map[string]*Blender{
+		{"ok": valid},
+		{"ok": valid, "bad": invalid},
+	}
+	for _, test := range tests {
+		if _, err := CheckStates(test, color.OKLab); err == nil {
+			t.Errorf("Wanted error for %v", test)
+		}
+	}
+}
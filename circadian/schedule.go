@@ -0,0 +1,200 @@
+package circadian
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gazek/color-blender/blender"
+	"github.com/gazek/color-blender/color"
+	"github.com/gazek/color-blender/transfunc"
+)
+
+// Day is the length of a schedule
+const Day = 24 * time.Hour
+
+// Level is the color temperature and brightness of the light
+type Level struct {
+	Kelvin     float64
+	Brightness uint8
+}
+
+// Keyframe is the level the light reaches at a time of day, the light changes evenly between keyframes
+type Keyframe struct {
+	// At is the time after midnight
+	At time.Duration
+	Level
+}
+
+// Schedule describes a day of light: a wake-up ramp from night to day, a neutral day,
+// an evening ramp to a warm evening and a night ramp to a dim night. Times are measured from midnight.
+type Schedule struct {
+	// Wake is when the wake-up ramp ends
+	Wake time.Duration
+	// Sunset is when the evening ramp starts
+	Sunset time.Duration
+	// Bedtime is when the night ramp starts
+	Bedtime time.Duration
+	// Ramp is how long each ramp lasts
+	Ramp    time.Duration
+	Night   Level
+	Day     Level
+	Evening Level
+}
+
+// NewSchedule creates a Schedule with a 7:00 wake, 19:00 sunset, 22:30 bedtime and half hour ramps
+func NewSchedule() Schedule {
+	return Schedule{
+		Wake:    7 * time.Hour,
+		Sunset:  19 * time.Hour,
+		Bedtime: 22*time.Hour + 30*time.Minute,
+		Ramp:    30 * time.Minute,
+		Night:   Level{Kelvin: 1900, Brightness: 25},
+		Day:     Level{Kelvin: 5000, Brightness: 255},
+		Evening: Level{Kelvin: 2700, Brightness: 150},
+	}
+}
+
+// NewSunSchedule creates a Schedule that wakes at sunrise and warms at sunset for the date and location,
+// see SunTimes. The bedtime is moved later if the evening ramp would run past it.
+func NewSunSchedule(date time.Time, latitude float64, longitude float64) (Schedule, error) {
+	sunrise, sunset, err := SunTimes(date, latitude, longitude)
+	if err != nil {
+		return Schedule{}, err
+	}
+	s := NewSchedule()
+	s.Wake = timeOfDay(sunrise)
+	s.Sunset = timeOfDay(sunset)
+	if s.Bedtime < s.Sunset+s.Ramp {
+		s.Bedtime = s.Sunset + s.Ramp
+	}
+	return s, nil
+}
+
+// Keyframes returns the keyframes of the schedule in order, starting with the start of the wake-up ramp
+func (s Schedule) Keyframes() []Keyframe {
+	keyframes := []Keyframe{
+		{s.Wake - s.Ramp, s.Night},
+		{s.Wake, s.Day},
+		{s.Sunset, s.Day},
+		{s.Sunset + s.Ramp, s.Evening},
+		{s.Bedtime, s.Evening},
+		{s.Bedtime + s.Ramp, s.Night},
+	}
+	// wrap the times into a single day
+	for i := range keyframes {
+		keyframes[i].At = ((keyframes[i].At % Day) + Day) % Day
+	}
+	return keyframes
+}
+
+// Validate checks that the keyframes are in order around the day and the levels are usable
+func (s Schedule) Validate() error {
+	if s.Ramp < 0 {
+		return errors.New("ramp must not be negative")
+	}
+	for _, l := range []Level{s.Night, s.Day, s.Evening} {
+		if l.Kelvin <= 0 {
+			return fmt.Errorf("invalid color temperature %v", l.Kelvin)
+		}
+	}
+	keyframes := s.Keyframes()
+	// each keyframe must come no earlier than the one before, counting from the first
+	var last time.Duration
+	for i := range keyframes {
+		offset := (keyframes[i].At - keyframes[0].At + Day) % Day
+		if offset < last {
+			return fmt.Errorf("keyframe %d at %v is out of order", i, keyframes[i].At)
+		}
+		last = offset
+	}
+	return nil
+}
+
+// Blender creates a blender whose step is the time of day, each step lasts stepDuration which must divide
+// a day evenly. Its period is one day, use StepAt to find the step for a time.
+func (s Schedule) Blender(stepDuration time.Duration) (*blender.Blender, error) {
+	if stepDuration <= 0 || Day%stepDuration != 0 {
+		return nil, fmt.Errorf("step duration %v must divide a day evenly", stepDuration)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	stepsPerDay := int(Day / stepDuration)
+	keyframes := s.Keyframes()
+	// split the ramps between keyframes into pieces that don't cross midnight
+	var pieces []piece
+	for i := range keyframes {
+		from, to := keyframes[i], keyframes[(i+1)%len(keyframes)]
+		start := int(math.Round(float64(from.At)/float64(stepDuration))) % stepsPerDay
+		end := int(math.Round(float64(to.At)/float64(stepDuration))) % stepsPerDay
+		length := (end - start + stepsPerDay) % stepsPerDay
+		if length == 0 {
+			continue
+		}
+		if start+length <= stepsPerDay {
+			pieces = append(pieces, piece{start, length, from.Level, to.Level, 0, 1})
+			continue
+		}
+		split := float32(stepsPerDay-start) / float32(length)
+		pieces = append(pieces, piece{start, stepsPerDay - start, from.Level, to.Level, 0, split})
+		pieces = append(pieces, piece{0, length - (stepsPerDay - start), from.Level, to.Level, split, 1})
+	}
+	if len(pieces) == 0 {
+		return nil, errors.New("schedule has no ramps or holds")
+	}
+	sortPieces(pieces)
+	// add the funcs in order from midnight
+	b := &blender.Blender{}
+	for _, p := range pieces {
+		b.AppendColorFunc(p.colorFunc())
+		b.AppendBrightnessFunc(p.brightnessFunc())
+	}
+	return b, nil
+}
+
+// StepAt returns the step of a blender from Schedule.Blender for the clock time of t
+func StepAt(t time.Time, stepDuration time.Duration) int {
+	return int(timeOfDay(t) / stepDuration)
+}
+
+// piece is the part of the change between two levels that falls within a range of steps
+type piece struct {
+	start  int
+	length int
+	from   Level
+	to     Level
+	// inputStart and inputEnd are the fractions of the change between the levels covered by the piece
+	inputStart float32
+	inputEnd   float32
+}
+
+// colorFunc returns a ColorFunc that changes the color temperature over the piece
+func (p piece) colorFunc() transfunc.ColorFunc {
+	transType := transfunc.Temperature
+	// holds stay exactly on the color
+	if p.from.Kelvin == p.to.Kelvin {
+		transType = transfunc.AllAtOnce
+	}
+	return transfunc.NewColorFunc(color.Kelvin(p.from.Kelvin), color.Kelvin(p.to.Kelvin), transType,
+		func(x float32) float32 { return x }, p.length, []float32{p.inputStart, p.inputEnd})
+}
+
+// brightnessFunc returns a BrightnessFunc that changes the brightness over the piece
+func (p piece) brightnessFunc() transfunc.BrightnessFunc {
+	from, to := float32(p.from.Brightness), float32(p.to.Brightness)
+	return transfunc.NewBrightnessFunc(func(x float32) float32 {
+		// the brightness is truncated, so add a half to round it
+		return float32(math.Min(1, float64((from+(to-from)*x+0.5)/math.MaxUint8)))
+	}, p.length, []float32{p.inputStart, p.inputEnd})
+}
+
+// sortPieces sorts the pieces by start step with an insertion sort, since TinyGo can't use sort.Slice
+func sortPieces(a []piece) {
+	for i := 1; i < len(a); i++ {
+		for j := i; j > 0 && a[j-1].start > a[j].start; j-- {
+			a[j-1], a[j] = a[j], a[j-1]
+		}
+	}
+}
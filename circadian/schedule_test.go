@@ -0,0 +1,128 @@
+package circadian
+
+import (
+	ic "image/color"
+	"testing"
+	"time"
+
+	"github.com/gazek/color-blender/color"
+)
+
+func TestKeyframes(t *testing.T) {
+	s := NewSchedule()
+	s.Bedtime = 23*time.Hour + 45*time.Minute
+	want := []time.Duration{
+		6*time.Hour + 30*time.Minute,
+		7 * time.Hour,
+		19 * time.Hour,
+		19*time.Hour + 30*time.Minute,
+		23*time.Hour + 45*time.Minute,
+		15 * time.Minute,
+	}
+	keyframes := s.Keyframes()
+	for i := range want {
+		if keyframes[i].At != want[i] {
+			t.Errorf("Wanted %v, got: %v", want[i], keyframes[i].At)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		modify  func(s *Schedule)
+		wantErr bool
+	}{
+		{func(s *Schedule) {}, false},
+		{func(s *Schedule) { s.Ramp = -time.Minute }, true},
+		{func(s *Schedule) { s.Day.Kelvin = 0 }, true},
+		// the evening ramp runs past bedtime
+		{func(s *Schedule) { s.Bedtime = 19*time.Hour + 15*time.Minute }, true},
+		// the night ramp runs past the start of the wake-up ramp
+		{func(s *Schedule) { s.Bedtime = 6*time.Hour + 15*time.Minute + 24*time.Hour }, true},
+		{func(s *Schedule) { s.Ramp = 0 }, false},
+	}
+
+	for _, test := range tests {
+		s := NewSchedule()
+		test.modify(&s)
+		if err := s.Validate(); (err != nil) != test.wantErr {
+			t.Errorf("Wanted error %v, got: %v", test.wantErr, err)
+		}
+	}
+}
+
+func TestBlender(t *testing.T) {
+	s := NewSchedule()
+	// the night ramp crosses midnight
+	s.Bedtime = 23*time.Hour + 45*time.Minute
+	b, err := s.Blender(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.GetPeriod() != 1440 {
+		t.Errorf("Wanted %v, got: %v", 1440, b.GetPeriod())
+	}
+	night := color.Kelvin(1900)
+	night.A = 25
+	day := color.Kelvin(5000)
+	day.A = 255
+	evening := color.Kelvin(2700)
+	evening.A = 150
+	tests := []struct {
+		at   time.Duration
+		want ic.RGBA
+	}{
+		{6 * time.Hour, night},
+		{7 * time.Hour, day},
+		{12 * time.Hour, day},
+		{20 * time.Hour, evening},
+		{23*time.Hour + 45*time.Minute, evening},
+		// half way through the night ramp
+		{0, ic.RGBA{R: 255, G: 151, B: 49, A: 88}},
+		{15 * time.Minute, night},
+	}
+
+	for _, test := range tests {
+		if result := b.GetColorAtStep(int(test.at / time.Minute)).GetColor(); result != test.want {
+			t.Errorf("Wanted %v at %v, got: %v", test.want, test.at, result)
+		}
+	}
+}
+
+func TestBlenderErrors(t *testing.T) {
+	s := NewSchedule()
+	if _, err := s.Blender(7 * time.Minute); err == nil {
+		t.Errorf("Wanted error for a step that doesn't divide a day")
+	}
+	s.Ramp = -time.Minute
+	if _, err := s.Blender(time.Minute); err == nil {
+		t.Errorf("Wanted error for an invalid schedule")
+	}
+}
+
+func TestNewSunSchedule(t *testing.T) {
+	s, err := NewSunSchedule(time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC), 51.5074, -0.1278)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// London sunrise and sunset in UTC
+	if d := s.Wake - (3*time.Hour + 43*time.Minute); d < -3*time.Minute || d > 3*time.Minute {
+		t.Errorf("Wanted %v, got: %v", 3*time.Hour+43*time.Minute, s.Wake)
+	}
+	if d := s.Sunset - (20*time.Hour + 21*time.Minute); d < -3*time.Minute || d > 3*time.Minute {
+		t.Errorf("Wanted %v, got: %v", 20*time.Hour+21*time.Minute, s.Sunset)
+	}
+	if err := s.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := NewSunSchedule(time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC), 69.6492, 18.9553); err != ErrPolarNight {
+		t.Errorf("Wanted %v, got: %v", ErrPolarNight, err)
+	}
+}
+
+func TestStepAt(t *testing.T) {
+	d := time.Date(2024, 1, 2, 13, 4, 59, 0, time.UTC)
+	if result := StepAt(d, time.Minute); result != 784 {
+		t.Errorf("Wanted %v, got: %v", 784, result)
+	}
+}
@@ -0,0 +1,58 @@
+package circadian
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	// ErrPolarNight is returned when the sun doesn't rise on the date
+	ErrPolarNight = errors.New("the sun does not rise on this date")
+	// ErrMidnightSun is returned when the sun doesn't set on the date
+	ErrMidnightSun = errors.New("the sun does not set on this date")
+)
+
+// SunTimes calculates the sunrise and sunset for the calendar date of date in its location,
+// at the latitude and longitude in degrees, north and east positive.
+// It uses the NOAA general solar position equations, which are accurate to a few minutes.
+func SunTimes(date time.Time, latitude float64, longitude float64) (sunrise time.Time, sunset time.Time, err error) {
+	year, month, day := date.Date()
+	noonUTC := time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	// fractional year in radians
+	gamma := 2 * math.Pi / 365 * float64(noonUTC.YearDay()-1)
+	// equation of time in minutes and solar declination in radians
+	eqTime := 229.18 * (0.000075 + 0.001868*math.Cos(gamma) - 0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) - 0.040849*math.Sin(2*gamma))
+	decl := 0.006918 - 0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma)
+	// hour angle of the sun's upper limb on the horizon, allowing for refraction
+	lat := latitude * math.Pi / 180
+	cosHA := math.Cos(90.833*math.Pi/180)/(math.Cos(lat)*math.Cos(decl)) - math.Tan(lat)*math.Tan(decl)
+	if cosHA > 1 {
+		return time.Time{}, time.Time{}, ErrPolarNight
+	}
+	if cosHA < -1 {
+		return time.Time{}, time.Time{}, ErrMidnightSun
+	}
+	ha := math.Acos(cosHA) * 180 / math.Pi
+	// minutes after midnight UTC
+	midnightUTC := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	rise := 720 - 4*(longitude+ha) - eqTime
+	set := 720 - 4*(longitude-ha) - eqTime
+	sunrise = midnightUTC.Add(minutes(rise)).In(date.Location())
+	sunset = midnightUTC.Add(minutes(set)).In(date.Location())
+	return sunrise, sunset, nil
+}
+
+// minutes converts fractional minutes to a duration
+func minutes(m float64) time.Duration {
+	return time.Duration(m * float64(time.Minute))
+}
+
+// timeOfDay returns how long after midnight the clock time of t is
+func timeOfDay(t time.Time) time.Duration {
+	h, m, s := t.Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(t.Nanosecond())
+}
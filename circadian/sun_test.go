@@ -0,0 +1,69 @@
+package circadian
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSunTimes(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	tests := []struct {
+		date        time.Time
+		lat, lon    float64
+		wantSunrise time.Time
+		wantSunset  time.Time
+	}{
+		{
+			time.Date(2024, 6, 21, 12, 0, 0, 0, newYork), 40.7128, -74.0060,
+			time.Date(2024, 6, 21, 5, 25, 0, 0, newYork), time.Date(2024, 6, 21, 20, 31, 0, 0, newYork),
+		},
+		{
+			time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC), 51.5074, -0.1278,
+			time.Date(2024, 12, 21, 8, 4, 0, 0, time.UTC), time.Date(2024, 12, 21, 15, 54, 0, 0, time.UTC),
+		},
+		{
+			time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), -33.8688, 151.2093,
+			time.Date(2024, 3, 19, 20, 0, 0, 0, time.UTC), time.Date(2024, 3, 20, 8, 9, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		sunrise, sunset, err := SunTimes(test.date, test.lat, test.lon)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := sunrise.Sub(test.wantSunrise); d < -3*time.Minute || d > 3*time.Minute {
+			t.Errorf("Wanted %v, got: %v", test.wantSunrise, sunrise)
+		}
+		if d := sunset.Sub(test.wantSunset); d < -3*time.Minute || d > 3*time.Minute {
+			t.Errorf("Wanted %v, got: %v", test.wantSunset, sunset)
+		}
+	}
+}
+
+func TestSunTimesPolar(t *testing.T) {
+	tests := []struct {
+		date time.Time
+		want error
+	}{
+		{time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC), ErrPolarNight},
+		{time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC), ErrMidnightSun},
+	}
+
+	for _, test := range tests {
+		if _, _, err := SunTimes(test.date, 69.6492, 18.9553); err != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, err)
+		}
+	}
+}
+
+func TestTimeOfDay(t *testing.T) {
+	d := time.Date(2024, 1, 2, 13, 4, 5, 6, time.UTC)
+	want := 13*time.Hour + 4*time.Minute + 5*time.Second + 6
+	if result := timeOfDay(d); result != want {
+		t.Errorf("Wanted %v, got: %v", want, result)
+	}
+}
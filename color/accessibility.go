@@ -0,0 +1,80 @@
+package color
+
+import (
+	"fmt"
+	ic "image/color"
+	"math"
+)
+
+// RelativeLuminance returns the WCAG relative luminance of a color, from 0 for black to 1 for white
+func RelativeLuminance(color ic.RGBA) float64 {
+	return 0.2126*decodeSRGB(color.R) + 0.7152*decodeSRGB(color.G) + 0.0722*decodeSRGB(color.B)
+}
+
+// ContrastRatio returns the WCAG contrast ratio between two colors, from 1 for equal luminance to 21 for black and white
+func ContrastRatio(a ic.RGBA, b ic.RGBA) float64 {
+	la, lb := RelativeLuminance(a), RelativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// Deficiency is a type of color vision
+type Deficiency int
+
+const (
+	// NormalVision sees colors unchanged
+	NormalVision Deficiency = iota
+	// Protanopia is the absence of the long wavelength (red) cones
+	Protanopia
+	// Deuteranopia is the absence of the medium wavelength (green) cones
+	Deuteranopia
+	// Tritanopia is the absence of the short wavelength (blue) cones
+	Tritanopia
+)
+
+// Deficiencies lists every type of color vision that can be simulated
+var Deficiencies = []Deficiency{NormalVision, Protanopia, Deuteranopia, Tritanopia}
+
+func (d Deficiency) String() string {
+	if d < NormalVision || d > Tritanopia {
+		return fmt.Sprintf("Deficiency(%d)", int(d))
+	}
+	return [...]string{"NormalVision", "Protanopia", "Deuteranopia", "Tritanopia"}[d]
+}
+
+// deficiencyMatrices are the full severity simulation matrices of Machado, Oliveira and Fernandes (2009)
+// that apply to linear RGB
+var deficiencyMatrices = [...][3][3]float64{
+	Protanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	Deuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	Tritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// Simulate returns the color as it appears with the color vision deficiency, alpha is unchanged
+func Simulate(color ic.RGBA, deficiency Deficiency) ic.RGBA {
+	if deficiency <= NormalVision || deficiency > Tritanopia {
+		return color
+	}
+	m := deficiencyMatrices[deficiency]
+	linear := [3]float64{decodeSRGB(color.R), decodeSRGB(color.G), decodeSRGB(color.B)}
+	var result [3]uint8
+	for i := range result {
+		v := m[i][0]*linear[0] + m[i][1]*linear[1] + m[i][2]*linear[2]
+		result[i] = encodeSRGB(math.Max(0, math.Min(1, v)))
+	}
+	return ic.RGBA{R: result[0], G: result[1], B: result[2], A: color.A}
+}
@@ -0,0 +1,92 @@
+package color
+
+import (
+	ic "image/color"
+	"math"
+	"testing"
+)
+
+func TestRelativeLuminance(t *testing.T) {
+	tests := []struct {
+		color ic.RGBA
+		want  float64
+	}{
+		{ic.RGBA{}, 0},
+		{ic.RGBA{R: 255, G: 255, B: 255}, 1},
+		{ic.RGBA{R: 255}, 0.2126},
+		{ic.RGBA{R: 128, G: 128, B: 128}, 0.2159},
+	}
+
+	for _, test := range tests {
+		if result := RelativeLuminance(test.color); math.Abs(result-test.want) > 0.0001 {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		a, b ic.RGBA
+		want float64
+	}{
+		{ic.RGBA{}, ic.RGBA{R: 255, G: 255, B: 255}, 21},
+		{ic.RGBA{R: 255, G: 255, B: 255}, ic.RGBA{}, 21},
+		{ic.RGBA{R: 255}, ic.RGBA{R: 255, G: 255, B: 255}, 3.9985},
+		{ic.RGBA{G: 255}, ic.RGBA{G: 255}, 1},
+	}
+
+	for _, test := range tests {
+		if result := ContrastRatio(test.a, test.b); math.Abs(result-test.want) > 0.0001 {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
+
+func TestDeficiencyString(t *testing.T) {
+	tests := map[Deficiency]string{
+		NormalVision:  "NormalVision",
+		Protanopia:    "Protanopia",
+		Deuteranopia:  "Deuteranopia",
+		Tritanopia:    "Tritanopia",
+		Deficiency(4): "Deficiency(4)",
+	}
+	for d, want := range tests {
+		if got := d.String(); got != want {
+			t.Errorf("Wanted %v, got: %v", want, got)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	red, green, blue := ic.RGBA{R: 255, A: 10}, ic.RGBA{G: 255}, ic.RGBA{B: 255}
+	white := ic.RGBA{R: 255, G: 255, B: 255}
+	// normal vision is unchanged
+	if result := Simulate(red, NormalVision); result != red {
+		t.Errorf("Wanted %v, got: %v", red, result)
+	}
+	for _, d := range Deficiencies {
+		// white and black stay the same since each row of the matrices adds up to one
+		if result := Simulate(white, d); CIEDE2000.Distance(result, white) > 1 {
+			t.Errorf("Wanted %v, got: %v for %v", white, result, d)
+		}
+		if result := Simulate(ic.RGBA{}, d); result != (ic.RGBA{}) {
+			t.Errorf("Wanted %v, got: %v for %v", ic.RGBA{}, result, d)
+		}
+		// alpha is unchanged
+		if result := Simulate(red, d); result.A != red.A {
+			t.Errorf("Wanted %v, got: %v for %v", red.A, result.A, d)
+		}
+	}
+	// red and green are much harder to tell apart without red or green cones
+	normal := CIEDE2000.Distance(red, green)
+	for _, d := range []Deficiency{Protanopia, Deuteranopia} {
+		if result := CIEDE2000.Distance(Simulate(red, d), Simulate(green, d)); result > normal/2 {
+			t.Errorf("Wanted less than %v, got: %v for %v", normal/2, result, d)
+		}
+	}
+	// blue and green are harder to tell apart without blue cones
+	normal = CIEDE2000.Distance(blue, green)
+	if result := CIEDE2000.Distance(Simulate(blue, Tritanopia), Simulate(green, Tritanopia)); result > normal*0.6 {
+		t.Errorf("Wanted less than %v, got: %v", normal*0.6, result)
+	}
+}
@@ -1,6 +1,7 @@
 package color
 
 import (
+	"fmt"
 	ic "image/color"
 	"math"
 )
@@ -43,98 +44,100 @@ func (c *Color) SetWhiteLevel(whiteLevel uint8) {
 	c.color = c.applyWhiteLevel(c.color, whiteLevel)
 }
 
+// SetWhiteLevelWithWhitePoint applies a white level to the current color, mixing in the white point
+// rather than equal RGB white. A white point from Kelvin gives a warm or cool white.
+func (c *Color) SetWhiteLevelWithWhitePoint(whiteLevel uint8, whitePoint ic.RGBA) {
+	c.whiteLevel = whiteLevel
+	base, _, value := c.decompose(c.color)
+	result := c.composeWithWhitePoint(base, whiteLevel, value, whitePoint)
+	result.A = c.color.A
+	c.color = result
+}
+
 // GetColor returns the current color
 func (c *Color) GetColor() ic.RGBA {
 	return c.color
 }
 
+// GetWhiteLevel returns the white level of the color
+func (c *Color) GetWhiteLevel() uint8 {
+	return c.whiteLevel
+}
+
 // GetBaseColor removes white and black from an rgb color
 func (c *Color) GetBaseColor(color ic.RGBA) ic.RGBA {
-	// check if the color is true white
-	if c.isWhite(color) {
-		return ic.RGBA{R: whiteBaseR, G: whiteBaseG, B: whiteBaseB, A: color.A}
-	}
-	// general case
-	// remove white level components from color
-	couleurSansBlanc := c.applyWhiteLevel(color, 0)
-	// scale the two non-zero component colors so that the dominant color is 255
-	baseColor := c.normalizeRGBLevels(couleurSansBlanc)
-	// return the base color
-	return baseColor
-}
-
-// GetColorDominance returns a slice of pointers and color component names sorted descending by color component value
-func (c *Color) GetColorDominance(color *ic.RGBA) (domPointers []*uint8, names []string) {
-	// create a slice of pointers to the RGB values
+	base, _, _ := c.decompose(color)
+	base.A = color.A
+	return base
+}
+
+// GetColorDominance returns a slice of pointers and color components sorted descending by color component value
+func (c *Color) GetColorDominance(color *ic.RGBA) (domPointers []*uint8, components []Component) {
+	// create a slice of pointers to the RGB values and the matching components
 	domPointers = []*uint8{&color.R, &color.G, &color.B}
-	// sort the pointers
-	domPointers = sortDomPointers(domPointers)
-	// get the names slice
-	names = c.getcolorDominanceNames(color, domPointers)
-	// return the sorted slice and names
-	return domPointers, names
+	components = []Component{Red, Green, Blue}
+	// sort them together so the components always match the pointers
+	sortDomPointers(domPointers, components)
+	// return the sorted slices
+	return domPointers, components
 }
 
 // sortDomPointers is an insertion sort implementation which is needed because
-// TinyGo panics on sort.Slice/SliceStable because reflect.Swapper is not impemented
-func sortDomPointers(a []*uint8) []*uint8 {
+// TinyGo panics on sort.Slice/SliceStable because reflect.Swapper is not impemented.
+// The components are swapped along with the pointers.
+func sortDomPointers(a []*uint8, components []Component) {
 	for i := 1; i < len(a); i++ {
 		j := i
 		for j > 0 {
 			if *a[j-1] < *a[j] {
 				a[j-1], a[j] = a[j], a[j-1]
+				components[j-1], components[j] = components[j], components[j-1]
 			}
 			j = j - 1
 		}
 	}
-	return a
-}
-
-// getcolorDominanceNames returns the color component names sorted descending by color component value
-func (c *Color) getcolorDominanceNames(color *ic.RGBA, domPointers []*uint8) []string {
-	var result []string
-	for d := range domPointers {
-		switch domPointers[d] {
-		case &color.R:
-			result = append(result, "R")
-		case &color.G:
-			result = append(result, "G")
-		case &color.B:
-			result = append(result, "B")
-		default:
-			panic("Failed to match dominance component")
-		}
-	}
-	return result
 }
 
-// GetComponentValue returns the component value of the color by component string
-func (c *Color) GetComponentValue(compName string) uint8 {
-	switch compName {
-	case "R":
-		return c.color.R
-	case "G":
-		return c.color.G
-	case "B":
-		return c.color.B
+// GetComponentValue returns the value of a color component, alpha is the brightness and white is the white level
+func (c *Color) GetComponentValue(comp Component) (uint8, error) {
+	switch comp {
+	case Red:
+		return c.color.R, nil
+	case Green:
+		return c.color.G, nil
+	case Blue:
+		return c.color.B, nil
+	case Alpha:
+		return c.color.A, nil
+	case White:
+		return c.whiteLevel, nil
 	default:
-		panic("Invalid color component name")
+		return 0, fmt.Errorf("invalid color component %v", comp)
 	}
 }
 
-// SetComponentValue set the R, G or B component value
-func (c *Color) SetComponentValue(compName string, value uint8) {
+// SetComponentValue sets the value of a color component, alpha is the brightness and white is the white level
+func (c *Color) SetComponentValue(comp Component, value uint8) error {
 	// set the color component
-	switch compName {
-	case "R":
+	switch comp {
+	case Red:
 		c.color.R = value
-	case "G":
+	case Green:
 		c.color.G = value
-	case "B":
+	case Blue:
 		c.color.B = value
+	case Alpha:
+		c.SetBrightness(value)
+		return nil
+	case White:
+		c.SetWhiteLevel(value)
+		return nil
+	default:
+		return fmt.Errorf("invalid color component %v", comp)
 	}
 	// set the color
 	c.SetColor(c.color)
+	return nil
 }
 
 // isWhite check if a color is true white
@@ -146,27 +149,7 @@ func (c *Color) isWhite(color ic.RGBA) bool {
 	return false
 }
 
-// getWhiteLevelComponent calculates an RGB tuple that represents the white components of the color
-func (c *Color) getWhiteLevelComponent(color ic.RGBA) ic.RGBA {
-	// check if the color is true white
-	if c.isWhite(color) {
-		return color
-	}
-	// general case
-	whiteLevelComp := ic.RGBA{R: color.R, G: color.G, B: color.B}
-	// get component dominaces if needed
-	dominance, _ := c.GetColorDominance(&whiteLevelComp)
-	// calculate the middle dominnce component's white level amount
-	whiteLevel := c.getWhiteLevel(whiteLevelComp, dominance)
-	*dominance[1] = *dominance[1] - uint8(((int(*dominance[1])*math.MaxUint8)-int(*dominance[0])*int(whiteLevel))/(math.MaxUint8-int(whiteLevel)))
-	// set dominant color component to 0 since none of it contributes to white
-	*dominance[0] = 0
-	// we leave the least dominant color component as is, since all of its value contributes to the white level
-	// return the result
-	return whiteLevelComp
-}
-
-// getWhiteLevel calculates the white level of the color
+// getWhiteLevel calculates the white level of the color, the ratio of the smallest to the largest component
 func (c *Color) getWhiteLevel(color ic.RGBA, dominance []*uint8) uint8 {
 	if dominance == nil {
 		dominance, _ = c.GetColorDominance(&color)
@@ -174,57 +157,77 @@ func (c *Color) getWhiteLevel(color ic.RGBA, dominance []*uint8) uint8 {
 	if *dominance[0] == 0 {
 		return 0
 	}
-	return uint8(int(math.MaxUint8) * int(*dominance[2]) / int(*dominance[0]))
+	return uint8(divRound(math.MaxUint8*int(*dominance[2]), int(*dominance[0])))
 }
 
-// applyWhiteLevel applies a white level to a color
+// applyWhiteLevel applies a white level to a color, keeping its base color and value
 func (c *Color) applyWhiteLevel(color ic.RGBA, whiteLevel uint8) ic.RGBA {
-	// get the white level components of the color
-	wlc := c.getWhiteLevelComponent(color)
-	// create a new RGB object that contains the color with zero white level
-	result := ic.RGBA{
-		R: color.R - wlc.R,
-		G: color.G - wlc.G,
-		B: color.B - wlc.B,
-		A: color.A,
-	}
-	// if the white level is zero then we are done
-	if whiteLevel == 0 {
-		return result
-	}
-	// get the color dominance
-	dom, _ := c.GetColorDominance(&result)
-	// modify the white level
-	for i := 0; i < 3; i++ {
-		adj := uint8((int(*dom[0] - *dom[i])) * int(whiteLevel) / math.MaxUint8)
-		*dom[i] += adj
-	}
-	// return the new color
+	base, _, value := c.decompose(color)
+	result := c.compose(base, whiteLevel, value)
+	result.A = color.A
 	return result
 }
 
-// normalizeRGBLevels removes white from a color
-func (c *Color) normalizeRGBLevels(color ic.RGBA) ic.RGBA {
-	// create a new RGB object to store the resulting color
-	result := ic.RGBA{
-		R: color.R,
-		G: color.G,
-		B: color.B,
-		A: color.A,
+// decompose splits a color into its base color, white level and value. The base color has its largest
+// component at 255 and its smallest at 0, the value is the largest component of the color.
+// compose reverses it to within one of the original for every component.
+func (c *Color) decompose(color ic.RGBA) (base ic.RGBA, whiteLevel uint8, value uint8) {
+	dom, _ := c.GetColorDominance(&color)
+	max, mid, min := int(*dom[0]), int(*dom[1]), int(*dom[2])
+	// black has no base color or white
+	if max == 0 {
+		return ic.RGBA{R: whiteBaseR, G: whiteBaseG, B: whiteBaseB}, 0, 0
 	}
-	// get the color dominance
-	dom, _ := c.GetColorDominance(&result)
-	// check base case
-	if *dom[0] == 0 {
-		result.R = whiteBaseR
-		result.G = whiteBaseG
-		result.B = whiteBaseB
-		return result
+	// true white is all white
+	if c.isWhite(color) {
+		return ic.RGBA{R: whiteBaseR, G: whiteBaseG, B: whiteBaseB}, math.MaxUint8, uint8(max)
 	}
-	// modify the white level
-	dom0 := int(*dom[0])
-	for i := 0; i < 3; i++ {
-		*dom[i] = uint8((int(*dom[i]) * int(math.MaxUint8)) / dom0)
+	// stretch the components so the smallest is 0 and the largest is 255,
+	// the pointers are reused so the base components land in the right place
+	*dom[0] = math.MaxUint8
+	*dom[1] = uint8(divRound(math.MaxUint8*(mid-min), max-min))
+	*dom[2] = 0
+	base = ic.RGBA{R: color.R, G: color.G, B: color.B}
+	return base, uint8(divRound(math.MaxUint8*min, max)), uint8(max)
+}
+
+// compose builds a color from a base color, white level and value. Each component is
+//
+//	value * (whiteLevel + base * (1 - whiteLevel))
+//
+// with the base and white level as fractions of 255, calculated in integers and rounded once.
+func (c *Color) compose(base ic.RGBA, whiteLevel uint8, value uint8) ic.RGBA {
+	return c.composeWithWhitePoint(base, whiteLevel, value, ic.RGBA{R: math.MaxUint8, G: math.MaxUint8, B: math.MaxUint8})
+}
+
+// composeWithWhitePoint is compose with the white mixed in as the white point instead of equal RGB,
+// the white point is scaled so its largest component is 255
+func (c *Color) composeWithWhitePoint(base ic.RGBA, whiteLevel uint8, value uint8, whitePoint ic.RGBA) ic.RGBA {
+	// find the largest white point component, black has no hue so it's treated as pure white
+	max := int(whitePoint.R)
+	if int(whitePoint.G) > max {
+		max = int(whitePoint.G)
 	}
-	return result
+	if int(whitePoint.B) > max {
+		max = int(whitePoint.B)
+	}
+	if max == 0 {
+		whitePoint = ic.RGBA{R: math.MaxUint8, G: math.MaxUint8, B: math.MaxUint8}
+		max = math.MaxUint8
+	}
+	component := func(b uint8, p uint8) uint8 {
+		w := int(whiteLevel)
+		n := int(value) * (w*int(p)*math.MaxUint8 + int(b)*(math.MaxUint8-w)*max)
+		return uint8(divRound(n, math.MaxUint8*math.MaxUint8*max))
+	}
+	return ic.RGBA{
+		R: component(base.R, whitePoint.R),
+		G: component(base.G, whitePoint.G),
+		B: component(base.B, whitePoint.B),
+	}
+}
+
+// divRound divides non-negative integers, rounding half up
+func divRound(n int, d int) int {
+	return (2*n + d) / (2 * d)
 }
@@ -2,6 +2,7 @@ package color
 
 import (
 	ic "image/color"
+	"math"
 	"testing"
 )
 
@@ -9,7 +10,7 @@ func TestGetColorDominanceSort(t *testing.T) {
 	color := ic.RGBA{R: 1, G: 2, B: 3, A: 4}
 	c := Color{}
 	d, names := c.GetColorDominance(&color)
-	wantNames := []string{"B", "G", "R"}
+	wantNames := []Component{Blue, Green, Red}
 	want := []uint8{3, 2, 1}
 	for i := range wantNames {
 		if wantNames[i] != names[i] {
@@ -28,7 +29,7 @@ func TestGetColorDominanceSortStability(t *testing.T) {
 	c := Color{}
 	d, names := c.GetColorDominance(&color)
 	want := []uint8{1, 2, 3}
-	wantNames := []string{"R", "G", "B"}
+	wantNames := []Component{Red, Green, Blue}
 	for i := range wantNames {
 		if wantNames[i] != names[i] {
 			t.Errorf("Want: %v, found: %v", wantNames[i], names[i])
@@ -47,29 +48,56 @@ func TestGetColorDominanceSortStability(t *testing.T) {
 
 func TestGetComponentValue(t *testing.T) {
 	color := ic.RGBA{R: 1, G: 2, B: 3, A: 4}
-	c := Color{color: color}
-	want := []uint8{1, 2, 3}
-	keys := []string{"R", "G", "B"}
+	c := Color{color: color, whiteLevel: 5}
+	want := []uint8{1, 2, 3, 4, 5}
+	keys := []Component{Red, Green, Blue, Alpha, White}
 	for i := range keys {
-		result := c.GetComponentValue(keys[i])
+		result, err := c.GetComponentValue(keys[i])
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
 		if want[i] != result {
 			t.Errorf("Want: %v, found: %v", want[i], result)
 		}
 	}
+	if _, err := c.GetComponentValue(Component(9)); err == nil {
+		t.Errorf("Wanted error for invalid component")
+	}
 }
 
 func TestSetComponentValue(t *testing.T) {
 	color := ic.RGBA{R: 1, G: 2, B: 3, A: 4}
 	c := Color{color: color}
-	want := []uint8{10, 9, 8}
-	keys := []string{"R", "G", "B"}
+	want := []uint8{10, 9, 8, 7}
+	keys := []Component{Red, Green, Blue, Alpha}
 	for i := range keys {
-		c.SetComponentValue(keys[i], want[i])
-		result := c.GetComponentValue(keys[i])
+		if err := c.SetComponentValue(keys[i], want[i]); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		result, _ := c.GetComponentValue(keys[i])
 		if want[i] != result {
 			t.Errorf("Want: %v, found: %v", want[i], result)
 		}
 	}
+	if err := c.SetComponentValue(Component(9), 1); err == nil {
+		t.Errorf("Wanted error for invalid component")
+	}
+	if c.GetColor() != (ic.RGBA{R: 10, G: 9, B: 8, A: 7}) {
+		t.Errorf("Wanted %v, got: %v", ic.RGBA{R: 10, G: 9, B: 8, A: 7}, c.GetColor())
+	}
+}
+
+func TestSetComponentValueWhite(t *testing.T) {
+	c := NewColor(ic.RGBA{R: 255, G: 0, B: 0, A: 255})
+	if err := c.SetComponentValue(White, 255); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if got, _ := c.GetComponentValue(White); got != 255 {
+		t.Errorf("Wanted %v, got: %v", 255, got)
+	}
+	if want := (ic.RGBA{R: 255, G: 255, B: 255, A: 255}); c.GetColor() != want {
+		t.Errorf("Wanted %v, got: %v", want, c.GetColor())
+	}
 }
 
 func TestGetColor(t *testing.T) {
@@ -118,59 +146,132 @@ func TestGetWhiteLevel(t *testing.T) {
 	}
 }
 
-func TestGetWhiteLevelComponent(t *testing.T) {
+func TestDecompose(t *testing.T) {
 	tests := []struct {
-		color ic.RGBA
-		want  ic.RGBA
+		color          ic.RGBA
+		wantBase       ic.RGBA
+		wantWhiteLevel uint8
+		wantValue      uint8
 	}{
-		{ic.RGBA{R: 255, G: 255, B: 255}, ic.RGBA{R: 255, G: 255, B: 255}},
-		{ic.RGBA{R: 255, G: 0x0, B: 0x0}, ic.RGBA{R: 0, G: 0, B: 0}},
-		{ic.RGBA{R: 255, G: 150, B: 0x0}, ic.RGBA{R: 0, G: 0, B: 0}},
-		{ic.RGBA{R: 0x0, G: 0x0, B: 0x0}, ic.RGBA{R: 0x0, G: 0x0, B: 0x0}},
-		{ic.RGBA{R: 175, G: 255, B: 160}, ic.RGBA{R: 135, G: 0, B: 160}},
+		{ic.RGBA{R: 255, G: 255, B: 255}, ic.RGBA{R: whiteBaseR, G: whiteBaseG, B: whiteBaseB}, 255, 255},
+		{ic.RGBA{R: 255, G: 0x0, B: 0x0}, ic.RGBA{R: 255, G: 0, B: 0}, 0, 255},
+		{ic.RGBA{R: 255, G: 150, B: 0x0}, ic.RGBA{R: 255, G: 150, B: 0}, 0, 255},
+		{ic.RGBA{R: 0x0, G: 0x0, B: 0x0}, ic.RGBA{R: whiteBaseR, G: whiteBaseG, B: whiteBaseB}, 0, 0},
+		{ic.RGBA{R: 175, G: 255, B: 160}, ic.RGBA{R: 40, G: 255, B: 0}, 160, 255},
+		{ic.RGBA{R: 150, G: 75, B: 25}, ic.RGBA{R: 255, G: 102, B: 0}, 43, 150},
+		{ic.RGBA{R: 90, G: 90, B: 90}, ic.RGBA{R: whiteBaseR, G: whiteBaseG, B: whiteBaseB}, 255, 90},
 	}
 
 	c := Color{}
 	for _, test := range tests {
-		if result := c.getWhiteLevelComponent(test.color); result != test.want {
-			t.Errorf("Wanted %v, got: %v", test.want, result)
+		base, whiteLevel, value := c.decompose(test.color)
+		if base != test.wantBase || whiteLevel != test.wantWhiteLevel || value != test.wantValue {
+			t.Errorf("Wanted %v %v %v, got: %v %v %v", test.wantBase, test.wantWhiteLevel, test.wantValue, base, whiteLevel, value)
 		}
 	}
 }
 
-func TestApplyWhiteLevel(t *testing.T) {
+func TestCompose(t *testing.T) {
 	tests := []struct {
-		color      ic.RGBA
+		base       ic.RGBA
 		whiteLevel uint8
+		value      uint8
 		want       ic.RGBA
 	}{
-		{ic.RGBA{R: 255, G: 150, B: 0}, 0, ic.RGBA{R: 255, G: 150, B: 0}},
-		{ic.RGBA{R: 255, G: 150, B: 0}, 255, ic.RGBA{R: 255, G: 255, B: 255}},
-		{ic.RGBA{R: 255, G: 150, B: 0}, 127, ic.RGBA{R: 255, G: 202, B: 127}},
-		{ic.RGBA{R: 150, G: 75, B: 25}, 0, ic.RGBA{R: 150, G: 60, B: 0}},
+		{ic.RGBA{R: 255, G: 150, B: 0}, 0, 255, ic.RGBA{R: 255, G: 150, B: 0}},
+		{ic.RGBA{R: 255, G: 150, B: 0}, 255, 255, ic.RGBA{R: 255, G: 255, B: 255}},
+		{ic.RGBA{R: 255, G: 150, B: 0}, 0, 0, ic.RGBA{R: 0, G: 0, B: 0}},
+		{ic.RGBA{R: 40, G: 255, B: 0}, 160, 255, ic.RGBA{R: 175, G: 255, B: 160}},
+		{ic.RGBA{R: whiteBaseR, G: whiteBaseG, B: whiteBaseB}, 255, 90, ic.RGBA{R: 90, G: 90, B: 90}},
 	}
 
 	c := Color{}
 	for _, test := range tests {
-		if result := c.applyWhiteLevel(test.color, test.whiteLevel); result != test.want {
+		if result := c.compose(test.base, test.whiteLevel, test.value); result != test.want {
 			t.Errorf("Wanted %v, got: %v", test.want, result)
 		}
 	}
 }
 
-func TestNormalizeRGBLevels(t *testing.T) {
+// checkRoundTrip fails the test if composing the decomposed color is more than one off in any component
+func checkRoundTrip(t *testing.T, color ic.RGBA) {
+	c := Color{}
+	base, whiteLevel, value := c.decompose(color)
+	result := c.compose(base, whiteLevel, value)
+	want := []uint8{color.R, color.G, color.B}
+	got := []uint8{result.R, result.G, result.B}
+	for i := range want {
+		if d := int(want[i]) - int(got[i]); d < -1 || d > 1 {
+			t.Fatalf("Wanted %v, got: %v", color, result)
+		}
+	}
+}
+
+func TestRoundTripAllColors(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping all 16M colors in short mode")
+	}
+	for r := 0; r <= math.MaxUint8; r++ {
+		for g := 0; g <= math.MaxUint8; g++ {
+			for b := 0; b <= math.MaxUint8; b++ {
+				checkRoundTrip(t, ic.RGBA{R: uint8(r), G: uint8(g), B: uint8(b)})
+			}
+		}
+	}
+}
+
+func FuzzRoundTrip(f *testing.F) {
+	f.Add(uint8(255), uint8(150), uint8(25))
+	f.Add(uint8(1), uint8(0), uint8(1))
+	f.Add(uint8(3), uint8(2), uint8(1))
+	f.Fuzz(func(t *testing.T, r uint8, g uint8, b uint8) {
+		checkRoundTrip(t, ic.RGBA{R: r, G: g, B: b})
+	})
+}
+
+func FuzzSetWhiteLevel(f *testing.F) {
+	f.Add(uint8(255), uint8(138), uint8(0), uint8(25))
+	f.Add(uint8(10), uint8(3), uint8(1), uint8(200))
+	f.Add(uint8(255), uint8(0), uint8(0), uint8(255))
+	f.Fuzz(func(t *testing.T, r uint8, g uint8, b uint8, whiteLevel uint8) {
+		c := NewColor(ic.RGBA{R: r, G: g, B: b})
+		base, _, value := c.decompose(c.color)
+		c.SetWhiteLevel(whiteLevel)
+		// at full value the smallest component is the white level, so it reads back exactly
+		full := c.compose(base, whiteLevel, math.MaxUint8)
+		if got := c.getWhiteLevel(full, nil); got != whiteLevel {
+			t.Errorf("Wanted %v, got: %v", whiteLevel, got)
+		}
+		// each component is the exact mix of the base color and white at the original value, rounded to the nearest
+		result := c.GetColor()
+		want := []uint8{base.R, base.G, base.B}
+		got := []uint8{result.R, result.G, result.B}
+		for i := range want {
+			exact := float64(value) * (float64(whiteLevel)*math.MaxUint8 + float64(want[i])*float64(math.MaxUint8-whiteLevel)) / (math.MaxUint8 * math.MaxUint8)
+			if d := math.Abs(float64(got[i]) - exact); d > 0.5+1e-9 {
+				t.Errorf("Wanted %v, got: %v", exact, got[i])
+			}
+		}
+		// and the result decomposes back to itself
+		checkRoundTrip(t, result)
+	})
+}
+
+func TestApplyWhiteLevel(t *testing.T) {
 	tests := []struct {
-		color ic.RGBA
-		want  ic.RGBA
+		color      ic.RGBA
+		whiteLevel uint8
+		want       ic.RGBA
 	}{
-		{ic.RGBA{R: 255, G: 150, B: 0}, ic.RGBA{R: 255, G: 150, B: 0}},
-		{ic.RGBA{R: 150, G: 75, B: 25}, ic.RGBA{R: 255, G: 127, B: 42}},
-		{ic.RGBA{R: 0, G: 0, B: 0}, ic.RGBA{R: whiteBaseR, G: whiteBaseG, B: whiteBaseB}},
+		{ic.RGBA{R: 255, G: 150, B: 0}, 0, ic.RGBA{R: 255, G: 150, B: 0}},
+		{ic.RGBA{R: 255, G: 150, B: 0}, 255, ic.RGBA{R: 255, G: 255, B: 255}},
+		{ic.RGBA{R: 255, G: 150, B: 0}, 127, ic.RGBA{R: 255, G: 202, B: 127}},
+		{ic.RGBA{R: 150, G: 75, B: 25}, 0, ic.RGBA{R: 150, G: 60, B: 0}},
 	}
 
 	c := Color{}
 	for _, test := range tests {
-		if result := c.normalizeRGBLevels(test.color); result != test.want {
+		if result := c.applyWhiteLevel(test.color, test.whiteLevel); result != test.want {
 			t.Errorf("Wanted %v, got: %v", test.want, result)
 		}
 	}
@@ -259,7 +360,7 @@ func TestSetWhiteLevel(t *testing.T) {
 		whiteLevel uint8
 		want       ic.RGBA
 	}{
-		{ic.RGBA{R: 255, G: 150, B: 25, A: 200}, 0, ic.RGBA{R: 255, G: 138, B: 0, A: 200}},
+		{ic.RGBA{R: 255, G: 150, B: 25, A: 200}, 0, ic.RGBA{R: 255, G: 139, B: 0, A: 200}},
 		{ic.RGBA{R: 255, G: 138, B: 0, A: 200}, 25, ic.RGBA{R: 255, G: 149, B: 25, A: 200}},
 	}
 
@@ -276,3 +377,42 @@ func TestSetWhiteLevel(t *testing.T) {
 		}
 	}
 }
+
+func TestWhiteLevelGetter(t *testing.T) {
+	c := NewColor(ic.RGBA{R: 255, G: 150, B: 51})
+	if result := c.GetWhiteLevel(); result != 51 {
+		t.Errorf("Wanted %v, got: %v", 51, result)
+	}
+	c.SetWhiteLevel(200)
+	if result := c.GetWhiteLevel(); result != 200 {
+		t.Errorf("Wanted %v, got: %v", 200, result)
+	}
+}
+
+func TestSetWhiteLevelWithWhitePoint(t *testing.T) {
+	tests := []struct {
+		color      ic.RGBA
+		whiteLevel uint8
+		whitePoint ic.RGBA
+		want       ic.RGBA
+	}{
+		{ic.RGBA{R: 255, A: 200}, 255, ic.RGBA{R: 255, G: 173, B: 89}, ic.RGBA{R: 255, G: 173, B: 89, A: 200}},
+		{ic.RGBA{R: 255, A: 200}, 128, ic.RGBA{R: 255, G: 173, B: 89}, ic.RGBA{R: 255, G: 87, B: 45, A: 200}},
+		// the white point is scaled to full value
+		{ic.RGBA{B: 100}, 255, ic.RGBA{R: 100, G: 50, B: 0}, ic.RGBA{R: 100, G: 50, B: 0}},
+		// a black white point is equal RGB white
+		{ic.RGBA{R: 255, G: 150}, 255, ic.RGBA{}, ic.RGBA{R: 255, G: 255, B: 255}},
+		{ic.RGBA{R: 255, G: 150}, 0, ic.RGBA{R: 255, G: 173, B: 89}, ic.RGBA{R: 255, G: 150}},
+	}
+
+	for _, test := range tests {
+		c := NewColor(test.color)
+		c.SetWhiteLevelWithWhitePoint(test.whiteLevel, test.whitePoint)
+		if c.GetColor() != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, c.GetColor())
+		}
+		if c.GetWhiteLevel() != test.whiteLevel {
+			t.Errorf("Wanted %v, got: %v", test.whiteLevel, c.GetWhiteLevel())
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package color
+
+import "fmt"
+
+// Component identifies a single channel of a color
+type Component int
+
+const (
+	// Red component
+	Red Component = iota
+	// Green component
+	Green
+	// Blue component
+	Blue
+	// Alpha component, the brightness of the color
+	Alpha
+	// White component, the white level of the color
+	White
+)
+
+// String returns the short name of the component
+func (c Component) String() string {
+	if c < Red || c > White {
+		return fmt.Sprintf("Component(%d)", int(c))
+	}
+	return [...]string{"R", "G", "B", "A", "W"}[c]
+}
@@ -0,0 +1,20 @@
+package color
+
+import "testing"
+
+func TestComponentString(t *testing.T) {
+	tests := map[Component]string{
+		Red:           "R",
+		Green:         "G",
+		Blue:          "B",
+		Alpha:         "A",
+		White:         "W",
+		Component(-1): "Component(-1)",
+		Component(5):  "Component(5)",
+	}
+	for c, want := range tests {
+		if got := c.String(); got != want {
+			t.Errorf("Wanted %v, got: %v", want, got)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package color
+
+import ic "image/color"
+
+// Decomposed is a color split into parts that can be blended independently
+type Decomposed struct {
+	// Base is the hue of the color with its largest component at 255 and its smallest at 0,
+	// black and true white use an arbitrary base color
+	Base ic.RGBA
+	// WhiteLevel is the ratio of the smallest to the largest component, 255 is true white
+	WhiteLevel uint8
+	// Value is the largest component of the color
+	Value uint8
+	// Brightness is the alpha of the color
+	Brightness uint8
+}
+
+// Decompose splits a color into its base color, white level, value and brightness
+func Decompose(color ic.RGBA) Decomposed {
+	c := Color{}
+	base, whiteLevel, value := c.decompose(color)
+	return Decomposed{Base: base, WhiteLevel: whiteLevel, Value: value, Brightness: color.A}
+}
+
+// Compose builds the color described by a Decomposed, it is within one of the decomposed color in every component
+func Compose(d Decomposed) ic.RGBA {
+	c := Color{}
+	result := c.compose(d.Base, d.WhiteLevel, d.Value)
+	result.A = d.Brightness
+	return result
+}
+
+// IsAchromatic reports whether the base color is meaningless because the color is black or true white
+func (d Decomposed) IsAchromatic() bool {
+	return d.Value == 0 || d.WhiteLevel == 0xff
+}
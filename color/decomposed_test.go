@@ -0,0 +1,44 @@
+package color
+
+import (
+	ic "image/color"
+	"testing"
+)
+
+func TestDecomposeCompose(t *testing.T) {
+	tests := []struct {
+		color ic.RGBA
+		want  Decomposed
+	}{
+		{ic.RGBA{R: 150, G: 75, B: 25, A: 200}, Decomposed{Base: ic.RGBA{R: 255, G: 102, B: 0}, WhiteLevel: 43, Value: 150, Brightness: 200}},
+		{ic.RGBA{R: 255, G: 150, B: 0, A: 255}, Decomposed{Base: ic.RGBA{R: 255, G: 150, B: 0}, WhiteLevel: 0, Value: 255, Brightness: 255}},
+		{ic.RGBA{R: 0, G: 0, B: 0, A: 10}, Decomposed{Base: ic.RGBA{R: whiteBaseR, G: whiteBaseG, B: whiteBaseB}, WhiteLevel: 0, Value: 0, Brightness: 10}},
+	}
+
+	for _, test := range tests {
+		d := Decompose(test.color)
+		if d != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, d)
+		}
+		if result := Compose(d); result != test.color {
+			t.Errorf("Wanted %v, got: %v", test.color, result)
+		}
+	}
+}
+
+func TestIsAchromatic(t *testing.T) {
+	tests := []struct {
+		color ic.RGBA
+		want  bool
+	}{
+		{ic.RGBA{R: 0, G: 0, B: 0}, true},
+		{ic.RGBA{R: 80, G: 80, B: 80}, true},
+		{ic.RGBA{R: 80, G: 80, B: 79}, false},
+	}
+
+	for _, test := range tests {
+		if result := Decompose(test.color).IsAchromatic(); result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
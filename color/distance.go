@@ -0,0 +1,166 @@
+package color
+
+import (
+	"fmt"
+	ic "image/color"
+	"math"
+)
+
+// Metric selects how the difference between two colors is measured, alpha is ignored
+type Metric int
+
+const (
+	// EuclideanRGB is the straight line distance between the sRGB components, from 0 to about 441
+	EuclideanRGB Metric = iota
+	// Redmean is the RGB distance weighted by the average red, a cheap approximation of perceived difference
+	Redmean
+	// CIE76 is the straight line distance in CIELAB, a difference of about 2.3 is just noticeable
+	CIE76
+	// CIEDE2000 is the CIE's most accurate color difference, a difference of about 1 is just noticeable
+	CIEDE2000
+	// OKLab is the straight line distance in Oklab, a difference of about 0.02 is just noticeable
+	OKLab
+)
+
+func (m Metric) String() string {
+	if m < EuclideanRGB || m > OKLab {
+		return fmt.Sprintf("Metric(%d)", int(m))
+	}
+	return [...]string{"EuclideanRGB", "Redmean", "CIE76", "CIEDE2000", "OKLab"}[m]
+}
+
+// Distance returns the difference between two colors
+func (m Metric) Distance(a ic.RGBA, b ic.RGBA) float64 {
+	switch m {
+	case Redmean:
+		return redmean(a, b)
+	case CIEDE2000:
+		return ciede2000(toLab(a), toLab(b))
+	default:
+		// the rest are euclidean in their own space
+		return distance(m.coords(a), m.coords(b))
+	}
+}
+
+// isEuclidean reports whether the metric is the straight line distance between the coords of the colors
+func (m Metric) isEuclidean() bool {
+	return m == EuclideanRGB || m == CIE76 || m == OKLab
+}
+
+// coords returns the position of the color in the space of a euclidean metric
+func (m Metric) coords(c ic.RGBA) [3]float64 {
+	switch m {
+	case CIE76:
+		return toLab(c)
+	case OKLab:
+		return toOKLab(c)
+	default:
+		return [3]float64{float64(c.R), float64(c.G), float64(c.B)}
+	}
+}
+
+// distance returns the straight line distance between two points
+func distance(a [3]float64, b [3]float64) float64 {
+	return math.Sqrt(squaredDistance(a, b))
+}
+
+// squaredDistance returns the square of the straight line distance between two points
+func squaredDistance(a [3]float64, b [3]float64) float64 {
+	d0, d1, d2 := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return d0*d0 + d1*d1 + d2*d2
+}
+
+// redmean weights the RGB differences by the average red of the colors
+func redmean(a ic.RGBA, b ic.RGBA) float64 {
+	rMean := (float64(a.R) + float64(b.R)) / 2
+	dr, dg, db := float64(a.R)-float64(b.R), float64(a.G)-float64(b.G), float64(a.B)-float64(b.B)
+	return math.Sqrt((2+rMean/256)*dr*dr + 4*dg*dg + (2+(255-rMean)/256)*db*db)
+}
+
+// toLab converts an sRGB color to CIELAB with the D65 white point
+func toLab(c ic.RGBA) [3]float64 {
+	X, Y, Z := toXYZ(c)
+	f := func(t float64) float64 {
+		const delta = 6.0 / 29
+		if t > delta*delta*delta {
+			return math.Cbrt(t)
+		}
+		return t/(3*delta*delta) + 4.0/29
+	}
+	fx, fy, fz := f(X/0.95047), f(Y), f(Z/1.08883)
+	return [3]float64{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
+
+// toOKLab converts an sRGB color to Oklab
+func toOKLab(c ic.RGBA) [3]float64 {
+	r, g, b := decodeSRGB(c.R), decodeSRGB(c.G), decodeSRGB(c.B)
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	return [3]float64{
+		0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// ciede2000 returns the CIEDE2000 difference between two CIELAB colors, following Sharma, Wu and Dalal
+func ciede2000(lab1 [3]float64, lab2 [3]float64) float64 {
+	const pow25to7 = 6103515625.0
+	deg := math.Pi / 180
+	l1, a1, b1 := lab1[0], lab1[1], lab1[2]
+	l2, a2, b2 := lab2[0], lab2[1], lab2[2]
+	// adjust a for the chroma
+	cBar := (math.Hypot(a1, b1) + math.Hypot(a2, b2)) / 2
+	cBar7 := math.Pow(cBar, 7)
+	g := 0.5 * (1 - math.Sqrt(cBar7/(cBar7+pow25to7)))
+	a1, a2 = (1+g)*a1, (1+g)*a2
+	c1, c2 := math.Hypot(a1, b1), math.Hypot(a2, b2)
+	hue := func(a, b float64) float64 {
+		if a == 0 && b == 0 {
+			return 0
+		}
+		return math.Mod(math.Atan2(b, a)/deg+360, 360)
+	}
+	h1, h2 := hue(a1, b1), hue(a2, b2)
+	// differences
+	dL := l2 - l1
+	dC := c2 - c1
+	dh := 0.0
+	if c1*c2 != 0 {
+		dh = h2 - h1
+		if dh > 180 {
+			dh -= 360
+		} else if dh < -180 {
+			dh += 360
+		}
+	}
+	dH := 2 * math.Sqrt(c1*c2) * math.Sin(dh/2*deg)
+	// means
+	lMean := (l1 + l2) / 2
+	cMean := (c1 + c2) / 2
+	hMean := h1 + h2
+	if c1*c2 != 0 {
+		switch {
+		case math.Abs(h1-h2) <= 180:
+			hMean /= 2
+		case h1+h2 < 360:
+			hMean = (hMean + 360) / 2
+		default:
+			hMean = (hMean - 360) / 2
+		}
+	}
+	// weights
+	t := 1 - 0.17*math.Cos((hMean-30)*deg) + 0.24*math.Cos(2*hMean*deg) +
+		0.32*math.Cos((3*hMean+6)*deg) - 0.20*math.Cos((4*hMean-63)*deg)
+	dTheta := 30 * math.Exp(-((hMean-275)/25)*((hMean-275)/25))
+	cMean7 := math.Pow(cMean, 7)
+	rC := 2 * math.Sqrt(cMean7/(cMean7+pow25to7))
+	sL := 1 + 0.015*(lMean-50)*(lMean-50)/math.Sqrt(20+(lMean-50)*(lMean-50))
+	sC := 1 + 0.045*cMean
+	sH := 1 + 0.015*cMean*t
+	rT := -math.Sin(2*dTheta*deg) * rC
+	// combine
+	lTerm, cTerm, hTerm := dL/sL, dC/sC, dH/sH
+	return math.Sqrt(lTerm*lTerm + cTerm*cTerm + hTerm*hTerm + rT*cTerm*hTerm)
+}
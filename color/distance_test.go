@@ -0,0 +1,113 @@
+package color
+
+import (
+	ic "image/color"
+	"math"
+	"testing"
+)
+
+func TestMetricString(t *testing.T) {
+	tests := map[Metric]string{
+		EuclideanRGB: "EuclideanRGB",
+		Redmean:      "Redmean",
+		CIE76:        "CIE76",
+		CIEDE2000:    "CIEDE2000",
+		OKLab:        "OKLab",
+		Metric(5):    "Metric(5)",
+	}
+	for m, want := range tests {
+		if got := m.String(); got != want {
+			t.Errorf("Wanted %v, got: %v", want, got)
+		}
+	}
+}
+
+func TestToLab(t *testing.T) {
+	tests := []struct {
+		color ic.RGBA
+		want  [3]float64
+	}{
+		{ic.RGBA{R: 255, G: 255, B: 255}, [3]float64{100, 0, 0}},
+		{ic.RGBA{R: 255}, [3]float64{53.2408, 80.0925, 67.2032}},
+		{ic.RGBA{B: 255}, [3]float64{32.2970, 79.1875, -107.8602}},
+		{ic.RGBA{}, [3]float64{0, 0, 0}},
+	}
+
+	for _, test := range tests {
+		if result := toLab(test.color); distance(result, test.want) > 0.01 {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
+
+func TestToOKLab(t *testing.T) {
+	tests := []struct {
+		color ic.RGBA
+		want  [3]float64
+	}{
+		{ic.RGBA{R: 255, G: 255, B: 255}, [3]float64{1, 0, 0}},
+		{ic.RGBA{R: 255}, [3]float64{0.62796, 0.22486, 0.12585}},
+		{ic.RGBA{}, [3]float64{0, 0, 0}},
+	}
+
+	for _, test := range tests {
+		if result := toOKLab(test.color); distance(result, test.want) > 0.0001 {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
+
+func TestCIEDE2000(t *testing.T) {
+	// pairs from the test data of Sharma, Wu and Dalal
+	tests := []struct {
+		lab1, lab2 [3]float64
+		want       float64
+	}{
+		{[3]float64{50, 2.6772, -79.7751}, [3]float64{50, 0, -82.7485}, 2.0425},
+		{[3]float64{50, 3.1571, -77.2803}, [3]float64{50, 0, -82.7485}, 2.8615},
+		{[3]float64{50, 2.8361, -74.0200}, [3]float64{50, 0, -82.7485}, 3.4412},
+		{[3]float64{50, 0, 0}, [3]float64{50, -1, 2}, 2.3669},
+		{[3]float64{50, 2.4900, -0.0010}, [3]float64{50, -2.4900, 0.0009}, 7.1792},
+		{[3]float64{50, 2.5, 0}, [3]float64{73, 25, -18}, 27.1492},
+		{[3]float64{50, 2.5, 0}, [3]float64{61, -5, 29}, 22.8977},
+		{[3]float64{60.2574, -34.0099, 36.2677}, [3]float64{60.4626, -34.1751, 39.4387}, 1.2644},
+		{[3]float64{2.0776, 0.0795, -1.1350}, [3]float64{0.9033, -0.0636, -0.5514}, 0.9082},
+	}
+
+	for _, test := range tests {
+		if result := ciede2000(test.lab1, test.lab2); math.Abs(result-test.want) > 0.0001 {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+		// the difference is symmetric
+		if result := ciede2000(test.lab2, test.lab1); math.Abs(result-test.want) > 0.0001 {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	black, white := ic.RGBA{}, ic.RGBA{R: 255, G: 255, B: 255}
+	tests := []struct {
+		metric Metric
+		want   float64
+	}{
+		{EuclideanRGB, 441.673},
+		{Redmean, 764.834},
+		{CIE76, 100},
+		{CIEDE2000, 100},
+		{OKLab, 1},
+	}
+
+	for _, test := range tests {
+		if result := test.metric.Distance(black, white); math.Abs(result-test.want) > 0.001 {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+		if result := test.metric.Distance(white, white); result != 0 {
+			t.Errorf("Wanted %v, got: %v", 0, result)
+		}
+	}
+	// alpha is ignored
+	if result := CIEDE2000.Distance(ic.RGBA{R: 10, A: 255}, ic.RGBA{R: 10}); result != 0 {
+		t.Errorf("Wanted %v, got: %v", 0, result)
+	}
+}
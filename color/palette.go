@@ -0,0 +1,149 @@
+package color
+
+import (
+	ic "image/color"
+	"math"
+)
+
+// kdTreeMinSize is the palette size at which a k-d tree is faster than checking every color
+const kdTreeMinSize = 16
+
+// Palette is a fixed set of colors that other colors can be matched to, such as the colors a fixture can show
+type Palette struct {
+	colors []ic.RGBA
+	metric Metric
+	// tree indexes the colors when the palette is large and the metric is euclidean
+	tree *kdNode
+}
+
+// NewPalette creates a Palette that matches colors using the metric
+func NewPalette(metric Metric, colors ...ic.RGBA) *Palette {
+	p := &Palette{
+		colors: append([]ic.RGBA(nil), colors...),
+		metric: metric,
+	}
+	// the metrics that aren't euclidean can't be searched with a k-d tree
+	if len(colors) >= kdTreeMinSize && metric.isEuclidean() {
+		points := make([]kdPoint, len(colors))
+		for i := range colors {
+			points[i] = kdPoint{coords: metric.coords(colors[i]), index: i}
+		}
+		p.tree = buildKDTree(points, 0)
+	}
+	return p
+}
+
+// Len returns the number of colors in the palette
+func (p *Palette) Len() int {
+	return len(p.colors)
+}
+
+// Colors returns a copy of the colors in the palette
+func (p *Palette) Colors() []ic.RGBA {
+	return append([]ic.RGBA(nil), p.colors...)
+}
+
+// Nearest returns the index and value of the palette color closest to the color, the index is -1 if the palette is empty
+func (p *Palette) Nearest(color ic.RGBA) (index int, nearest ic.RGBA) {
+	if len(p.colors) == 0 {
+		return -1, ic.RGBA{}
+	}
+	if p.tree != nil {
+		index = -1
+		best := math.Inf(1)
+		p.tree.nearest(p.metric.coords(color), &index, &best)
+		return index, p.colors[index]
+	}
+	// check every color, keeping the first of equally near colors
+	best := math.Inf(1)
+	for i := range p.colors {
+		if d := p.metric.Distance(color, p.colors[i]); d < best {
+			index, best = i, d
+		}
+	}
+	return index, p.colors[index]
+}
+
+// kdPoint is a palette color's position and index
+type kdPoint struct {
+	coords [3]float64
+	index  int
+}
+
+// kdNode is a node of a three dimensional k-d tree
+type kdNode struct {
+	point       kdPoint
+	axis        int
+	left, right *kdNode
+}
+
+// buildKDTree builds a balanced tree by splitting the points at the median of each axis in turn
+func buildKDTree(points []kdPoint, depth int) *kdNode {
+	if len(points) == 0 {
+		return nil
+	}
+	axis := depth % 3
+	median := len(points) / 2
+	selectNth(points, median, axis)
+	return &kdNode{
+		point: points[median],
+		axis:  axis,
+		left:  buildKDTree(points[:median], depth+1),
+		right: buildKDTree(points[median+1:], depth+1),
+	}
+}
+
+// nearest searches the tree for the point nearest the target, updating the best index and squared distance
+func (n *kdNode) nearest(target [3]float64, index *int, best *float64) {
+	if n == nil {
+		return
+	}
+	// prefer the lower index of equally near points, like the linear search
+	if d := squaredDistance(target, n.point.coords); d < *best || (d == *best && n.point.index < *index) {
+		*index, *best = n.point.index, d
+	}
+	// search the side of the split the target is on first
+	diff := target[n.axis] - n.point.coords[n.axis]
+	near, far := n.left, n.right
+	if diff > 0 {
+		near, far = far, near
+	}
+	near.nearest(target, index, best)
+	// the far side can only be nearer if the split is within the best distance
+	if diff*diff <= *best {
+		far.nearest(target, index, best)
+	}
+}
+
+// selectNth partially sorts the points so the nth point on the axis is in place, with no greater points
+// before it and no lesser points after it. It's a quickselect, since TinyGo can't use sort.Slice.
+func selectNth(points []kdPoint, n int, axis int) {
+	lo, hi := 0, len(points)-1
+	for lo < hi {
+		// partition around the middle point
+		pivot := points[(lo+hi)/2].coords[axis]
+		i, j := lo, hi
+		for i <= j {
+			for points[i].coords[axis] < pivot {
+				i++
+			}
+			for points[j].coords[axis] > pivot {
+				j--
+			}
+			if i <= j {
+				points[i], points[j] = points[j], points[i]
+				i++
+				j--
+			}
+		}
+		// keep going on the side that holds n
+		switch {
+		case n <= j:
+			hi = j
+		case n >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
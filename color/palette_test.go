@@ -0,0 +1,74 @@
+package color
+
+import (
+	ic "image/color"
+	"math/rand"
+	"testing"
+)
+
+func TestPaletteNearest(t *testing.T) {
+	p := NewPalette(CIEDE2000, ic.RGBA{R: 255}, ic.RGBA{G: 255}, ic.RGBA{B: 255}, ic.RGBA{R: 255, G: 255, B: 255}, ic.RGBA{})
+	tests := []struct {
+		color     ic.RGBA
+		wantIndex int
+	}{
+		{ic.RGBA{R: 200, G: 30, B: 20}, 0},
+		{ic.RGBA{R: 10, G: 180, B: 90}, 1},
+		{ic.RGBA{R: 20, G: 10, B: 140}, 2},
+		{ic.RGBA{R: 230, G: 230, B: 220}, 3},
+		{ic.RGBA{R: 20, G: 20, B: 20}, 4},
+	}
+
+	for _, test := range tests {
+		index, nearest := p.Nearest(test.color)
+		if index != test.wantIndex || nearest != p.colors[test.wantIndex] {
+			t.Errorf("Wanted %v, got: %v %v", test.wantIndex, index, nearest)
+		}
+	}
+	if index, _ := NewPalette(OKLab).Nearest(ic.RGBA{}); index != -1 {
+		t.Errorf("Wanted %v, got: %v", -1, index)
+	}
+}
+
+func TestPaletteKDTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomColor := func() ic.RGBA {
+		return ic.RGBA{R: uint8(r.Intn(256)), G: uint8(r.Intn(256)), B: uint8(r.Intn(256))}
+	}
+	colors := make([]ic.RGBA, 500)
+	for i := range colors {
+		colors[i] = randomColor()
+	}
+	// duplicates should resolve to the first like a linear search
+	colors[300] = colors[100]
+	for _, metric := range []Metric{EuclideanRGB, CIE76, OKLab} {
+		p := NewPalette(metric, colors...)
+		if p.tree == nil {
+			t.Fatalf("Wanted a k-d tree for %v", metric)
+		}
+		linear := &Palette{colors: colors, metric: metric}
+		for i := 0; i < 1000; i++ {
+			c := randomColor()
+			if i == 0 {
+				c = colors[100]
+			}
+			gotIndex, _ := p.Nearest(c)
+			wantIndex, _ := linear.Nearest(c)
+			if gotIndex != wantIndex {
+				t.Errorf("Wanted %v, got: %v for %v with %v", wantIndex, gotIndex, c, metric)
+			}
+		}
+	}
+	if NewPalette(CIEDE2000, colors...).tree != nil {
+		t.Errorf("Wanted no k-d tree for CIEDE2000")
+	}
+}
+
+func TestPaletteColors(t *testing.T) {
+	colors := []ic.RGBA{{R: 1}, {G: 2}}
+	p := NewPalette(EuclideanRGB, colors...)
+	colors[0] = ic.RGBA{B: 3}
+	if p.Len() != 2 || p.Colors()[0] != (ic.RGBA{R: 1}) {
+		t.Errorf("Wanted %v, got: %v", []ic.RGBA{{R: 1}, {G: 2}}, p.Colors())
+	}
+}
@@ -0,0 +1,228 @@
+package color
+
+import (
+	"fmt"
+	ic "image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Parse reads a color from a string. It accepts hex colors (#rgb, #rgba, #rrggbb and #rrggbbaa),
+// the CSS functions rgb(), rgba(), hsl() and hsla(), CSS color names, "transparent" and color
+// temperatures such as 2700K. Colors without an alpha are opaque.
+func Parse(s string) (ic.RGBA, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	// names come first since some end in k
+	if rgb, ok := namedColors[s]; ok {
+		return ic.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: math.MaxUint8}, nil
+	}
+	switch {
+	case strings.HasPrefix(s, "#"):
+		return parseHex(s[1:])
+	case strings.HasSuffix(s, ")"):
+		return parseFunction(s)
+	case strings.HasSuffix(s, "k"):
+		kelvin, err := strconv.ParseFloat(strings.TrimSuffix(s, "k"), 64)
+		if err != nil || kelvin <= 0 {
+			return ic.RGBA{}, fmt.Errorf("invalid color temperature %q", s)
+		}
+		return Kelvin(kelvin), nil
+	case s == "transparent":
+		return ic.RGBA{}, nil
+	}
+	return ic.RGBA{}, fmt.Errorf("unknown color %q", s)
+}
+
+// Format returns the color as #rrggbb, or #rrggbbaa if it isn't opaque
+func Format(color ic.RGBA) string {
+	if color.A == math.MaxUint8 {
+		return fmt.Sprintf("#%02x%02x%02x", color.R, color.G, color.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", color.R, color.G, color.B, color.A)
+}
+
+// String returns the color formatted by Format, it has a value receiver so Color values print too
+func (c Color) String() string {
+	return Format(c.color)
+}
+
+// MarshalText implements encoding.TextMarshaler for both Color values and pointers
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte(Format(c.color)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, it accepts anything Parse does
+func (c *Color) UnmarshalText(text []byte) error {
+	color, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	c.SetColor(color)
+	return nil
+}
+
+// parseHex reads the digits of a hex color
+func parseHex(s string) (ic.RGBA, error) {
+	// expand the short forms
+	if len(s) == 3 || len(s) == 4 {
+		long := make([]byte, 0, 2*len(s))
+		for i := range s {
+			long = append(long, s[i], s[i])
+		}
+		s = string(long)
+	}
+	if len(s) == 6 {
+		s += "ff"
+	}
+	if len(s) != 8 {
+		return ic.RGBA{}, fmt.Errorf("invalid hex color #%s", s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return ic.RGBA{}, fmt.Errorf("invalid hex color #%s", s)
+	}
+	return ic.RGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// parseFunction reads rgb(), rgba(), hsl() and hsla() in either the comma or the space separated form
+func parseFunction(s string) (ic.RGBA, error) {
+	open := strings.IndexByte(s, '(')
+	if open < 0 {
+		return ic.RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+	name := strings.TrimSpace(s[:open])
+	args := splitArgs(s[open+1 : len(s)-1])
+	if len(args) != 3 && len(args) != 4 {
+		return ic.RGBA{}, fmt.Errorf("%s() takes 3 or 4 values, got %d", name, len(args))
+	}
+	// the alpha is optional
+	alpha := uint8(math.MaxUint8)
+	if len(args) == 4 {
+		a, err := parseNumber(args[3], 1)
+		if err != nil {
+			return ic.RGBA{}, fmt.Errorf("invalid alpha %q: %w", args[3], err)
+		}
+		alpha = uint8(math.Round(a * math.MaxUint8))
+	}
+	switch name {
+	case "rgb", "rgba":
+		var rgb [3]uint8
+		for i := range rgb {
+			v, err := parseNumber(args[i], math.MaxUint8)
+			if err != nil {
+				return ic.RGBA{}, fmt.Errorf("invalid %s component %q: %w", name, args[i], err)
+			}
+			rgb[i] = uint8(math.Round(v))
+		}
+		return ic.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: alpha}, nil
+	case "hsl", "hsla":
+		h, err := parseHue(args[0])
+		if err != nil {
+			return ic.RGBA{}, fmt.Errorf("invalid hue %q: %w", args[0], err)
+		}
+		sat, err := parsePercent(args[1])
+		if err != nil {
+			return ic.RGBA{}, fmt.Errorf("invalid saturation %q: %w", args[1], err)
+		}
+		light, err := parsePercent(args[2])
+		if err != nil {
+			return ic.RGBA{}, fmt.Errorf("invalid lightness %q: %w", args[2], err)
+		}
+		color := hslToRGB(h, sat, light)
+		color.A = alpha
+		return color, nil
+	default:
+		return ic.RGBA{}, fmt.Errorf("unknown color function %s()", name)
+	}
+}
+
+// splitArgs splits function arguments separated by commas, or by spaces with a slash before the alpha
+func splitArgs(s string) []string {
+	if strings.Contains(s, ",") {
+		args := strings.Split(s, ",")
+		for i := range args {
+			args[i] = strings.TrimSpace(args[i])
+		}
+		return args
+	}
+	return strings.Fields(strings.Replace(s, "/", " ", 1))
+}
+
+// parseNumber reads a number from zero to max, or a percentage of max
+func parseNumber(s string, max float64) (float64, error) {
+	if strings.HasSuffix(s, "%") {
+		p, err := parsePercent(s)
+		return p * max, err
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 || v > max {
+		return 0, fmt.Errorf("out of range 0 to %v", max)
+	}
+	return v, nil
+}
+
+// parsePercent reads a percentage and returns it as a fraction
+func parsePercent(s string) (float64, error) {
+	if !strings.HasSuffix(s, "%") {
+		return 0, fmt.Errorf("missing %%")
+	}
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 || v > 100 {
+		return 0, fmt.Errorf("out of range 0%% to 100%%")
+	}
+	return v / 100, nil
+}
+
+// parseHue reads an angle in degrees, with an optional deg, rad or turn unit, and wraps it to [0, 360)
+func parseHue(s string) (float64, error) {
+	scale := 1.0
+	switch {
+	case strings.HasSuffix(s, "deg"):
+		s = strings.TrimSuffix(s, "deg")
+	case strings.HasSuffix(s, "rad"):
+		s = strings.TrimSuffix(s, "rad")
+		scale = 180 / math.Pi
+	case strings.HasSuffix(s, "turn"):
+		s = strings.TrimSuffix(s, "turn")
+		scale = 360
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return math.Mod(math.Mod(v*scale, 360)+360, 360), nil
+}
+
+// hslToRGB converts a hue in degrees and a saturation and lightness from zero to one
+func hslToRGB(h float64, s float64, l float64) ic.RGBA {
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - chroma/2
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return ic.RGBA{
+		R: uint8(math.Round((r + m) * math.MaxUint8)),
+		G: uint8(math.Round((g + m) * math.MaxUint8)),
+		B: uint8(math.Round((b + m) * math.MaxUint8)),
+	}
+}
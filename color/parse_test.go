@@ -0,0 +1,165 @@
+package color
+
+import (
+	"encoding/json"
+	"fmt"
+	ic "image/color"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want ic.RGBA
+	}{
+		{"#f80", ic.RGBA{R: 0xff, G: 0x88, B: 0x00, A: 0xff}},
+		{"#f808", ic.RGBA{R: 0xff, G: 0x88, B: 0x00, A: 0x88}},
+		{"#FF8000", ic.RGBA{R: 0xff, G: 0x80, B: 0x00, A: 0xff}},
+		{"#ff800080", ic.RGBA{R: 0xff, G: 0x80, B: 0x00, A: 0x80}},
+		{" rgb(255, 128, 0) ", ic.RGBA{R: 255, G: 128, B: 0, A: 255}},
+		{"rgb(255 128 0)", ic.RGBA{R: 255, G: 128, B: 0, A: 255}},
+		{"rgb(100%, 50%, 0%)", ic.RGBA{R: 255, G: 128, B: 0, A: 255}},
+		{"rgba(255, 128, 0, 0.5)", ic.RGBA{R: 255, G: 128, B: 0, A: 128}},
+		{"rgb(255 128 0 / 25%)", ic.RGBA{R: 255, G: 128, B: 0, A: 64}},
+		{"hsl(0, 100%, 50%)", ic.RGBA{R: 255, G: 0, B: 0, A: 255}},
+		{"hsl(120deg 100% 25%)", ic.RGBA{R: 0, G: 128, B: 0, A: 255}},
+		{"hsl(0.5turn, 100%, 50%)", ic.RGBA{R: 0, G: 255, B: 255, A: 255}},
+		{"hsl(-60, 100%, 50%)", ic.RGBA{R: 255, G: 0, B: 255, A: 255}},
+		{"hsla(210, 50%, 40%, 0.2)", ic.RGBA{R: 51, G: 102, B: 153, A: 51}},
+		{"hsl(0, 0%, 100%)", ic.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		{"RebeccaPurple", ic.RGBA{R: 0x66, G: 0x33, B: 0x99, A: 0xff}},
+		{"grey", ic.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}},
+		{"transparent", ic.RGBA{}},
+		{"2700K", ic.RGBA{R: 255, G: 173, B: 89, A: 255}},
+		{"6500k", ic.RGBA{R: 255, G: 249, B: 254, A: 255}},
+	}
+
+	for _, test := range tests {
+		result, err := Parse(test.s)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", test.s, err)
+		}
+		if result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"#12",
+		"#12345",
+		"#gggggg",
+		"rgb(1, 2)",
+		"rgb(256, 0, 0)",
+		"rgb(-1, 0, 0)",
+		"rgba(0, 0, 0, 2)",
+		"hsl(0, 100, 50%)",
+		"hsl(x, 100%, 50%)",
+		"cmyk(0, 0, 0, 0)",
+		"notacolor",
+		"-100K",
+		"warmK",
+	}
+
+	for _, test := range tests {
+		if _, err := Parse(test); err == nil {
+			t.Errorf("Wanted error for %q", test)
+		}
+	}
+}
+
+func TestNamedColors(t *testing.T) {
+	if len(namedColors) != 148 {
+		t.Errorf("Wanted %v, got: %v", 148, len(namedColors))
+	}
+	for name := range namedColors {
+		if _, err := Parse(name); err != nil {
+			t.Errorf("Unexpected error for %q: %v", name, err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		color ic.RGBA
+		want  string
+	}{
+		{ic.RGBA{R: 0xff, G: 0x80, B: 0x01, A: 0xff}, "#ff8001"},
+		{ic.RGBA{R: 0xff, G: 0x80, B: 0x01, A: 0x80}, "#ff800180"},
+		{ic.RGBA{}, "#00000000"},
+	}
+
+	for _, test := range tests {
+		result := Format(test.color)
+		if result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+		// formatted colors parse back to the same color
+		if parsed, _ := Parse(result); parsed != test.color {
+			t.Errorf("Wanted %v, got: %v", test.color, parsed)
+		}
+	}
+}
+
+func TestColorText(t *testing.T) {
+	var config struct {
+		Colors []*Color `json:"colors"`
+	}
+	if err := json.Unmarshal([]byte(`{"colors": ["red", "#00ff0080", "hsl(240, 100%, 50%)"]}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	want := []ic.RGBA{{R: 255, A: 255}, {G: 255, A: 128}, {B: 255, A: 255}}
+	for i := range want {
+		if config.Colors[i].GetColor() != want[i] {
+			t.Errorf("Wanted %v, got: %v", want[i], config.Colors[i].GetColor())
+		}
+	}
+	out, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"colors":["#ff0000","#00ff0080","#0000ff"]}` {
+		t.Errorf("Wanted %v, got: %v", `{"colors":["#ff0000","#00ff0080","#0000ff"]}`, string(out))
+	}
+	if err := json.Unmarshal([]byte(`{"colors": ["bogus"]}`), &config); err == nil {
+		t.Errorf("Wanted error for an unknown color")
+	}
+	if s := NewColor(ic.RGBA{R: 1, G: 2, B: 3, A: 255}).String(); s != "#010203" {
+		t.Errorf("Wanted %v, got: %v", "#010203", s)
+	}
+}
+
+func TestColorValueText(t *testing.T) {
+	type config struct {
+		Color  Color            `json:"c"`
+		Map    map[string]Color `json:"m"`
+		Colors []Color          `json:"s"`
+	}
+	in := `{"c":"#ff0000","m":{"a":"#00ff0080"},"s":["#0000ff","#010203"]}`
+	var c config
+	if err := json.Unmarshal([]byte(in), &c); err != nil {
+		t.Fatal(err)
+	}
+	// map elements can't be addressed, so copy one out to call the pointer methods
+	a := c.Map["a"]
+	if c.Color.GetColor() != (ic.RGBA{R: 255, A: 255}) || a.GetColor() != (ic.RGBA{G: 255, A: 128}) || c.Colors[1].GetColor() != (ic.RGBA{R: 1, G: 2, B: 3, A: 255}) {
+		t.Errorf("Unexpected colors: %v, %v, %v", c.Color, c.Map, c.Colors)
+	}
+	out, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != in {
+		t.Errorf("Wanted %v, got: %v", in, string(out))
+	}
+	// map keys use the text form too
+	keys, err := json.Marshal(map[Color]int{*NewColor(ic.RGBA{R: 255, A: 255}): 1})
+	if err != nil || string(keys) != `{"#ff0000":1}` {
+		t.Errorf("Wanted %v, got: %v, %v", `{"#ff0000":1}`, string(keys), err)
+	}
+	if s := fmt.Sprint(Color{color: ic.RGBA{B: 255, A: 255}}); s != "#0000ff" {
+		t.Errorf("Wanted %v, got: %v", "#0000ff", s)
+	}
+}
@@ -0,0 +1,95 @@
+package color
+
+import (
+	ic "image/color"
+	"math"
+)
+
+const (
+	// MinKelvin is the lowest color temperature Kelvin converts, lower temperatures are clamped
+	MinKelvin = 1667
+	// MaxKelvin is the highest color temperature Kelvin converts, higher temperatures are clamped
+	MaxKelvin = 25000
+)
+
+// Kelvin returns the full brightness color of a blackbody at the given temperature.
+// The chromaticity comes from the Kim et al. cubic spline approximation of the Planckian locus,
+// and is converted to sRGB with the largest component scaled to 255.
+func Kelvin(kelvin float64) ic.RGBA {
+	x, y := planckianLocus(math.Max(MinKelvin, math.Min(MaxKelvin, kelvin)))
+	// convert the chromaticity to XYZ with a luminance of one
+	X, Y, Z := x/y, 1.0, (1-x-y)/y
+	// convert XYZ to linear sRGB, the lowest temperatures are slightly outside the gamut
+	r := math.Max(0, 3.2404542*X-1.5371385*Y-0.4985314*Z)
+	g := math.Max(0, -0.9692660*X+1.8760108*Y+0.0415560*Z)
+	b := math.Max(0, 0.0556434*X-0.2040259*Y+1.0572252*Z)
+	// scale to full brightness and encode
+	max := math.Max(r, math.Max(g, b))
+	return ic.RGBA{
+		R: encodeSRGB(r / max),
+		G: encodeSRGB(g / max),
+		B: encodeSRGB(b / max),
+		A: math.MaxUint8,
+	}
+}
+
+// CCT estimates the correlated color temperature of a color with McCamy's approximation.
+// It is only meaningful for colors near the blackbody locus, black returns zero.
+func CCT(color ic.RGBA) float64 {
+	X, Y, Z := toXYZ(color)
+	sum := X + Y + Z
+	if sum == 0 {
+		return 0
+	}
+	x, y := X/sum, Y/sum
+	n := (x - 0.3320) / (0.1858 - y)
+	return 449*n*n*n + 3525*n*n + 6823.3*n + 5520.33
+}
+
+// planckianLocus returns the CIE 1931 chromaticity of a blackbody between MinKelvin and MaxKelvin
+func planckianLocus(t float64) (x float64, y float64) {
+	// x as a function of temperature
+	if t <= 4000 {
+		x = -0.2661239e9/(t*t*t) - 0.2343589e6/(t*t) + 0.8776956e3/t + 0.179910
+	} else {
+		x = -3.0258469e9/(t*t*t) + 2.1070379e6/(t*t) + 0.2226347e3/t + 0.240390
+	}
+	// y as a function of x
+	switch {
+	case t <= 2222:
+		y = -1.1063814*x*x*x - 1.34811020*x*x + 2.18555832*x - 0.20219683
+	case t <= 4000:
+		y = -0.9549476*x*x*x - 1.37418593*x*x + 2.09137015*x - 0.16748867
+	default:
+		y = 3.0817580*x*x*x - 5.87338670*x*x + 3.75112997*x - 0.37001483
+	}
+	return x, y
+}
+
+// toXYZ converts an sRGB color to CIE XYZ with the D65 white point, white has a Y of one
+func toXYZ(color ic.RGBA) (X float64, Y float64, Z float64) {
+	r, g, b := decodeSRGB(color.R), decodeSRGB(color.G), decodeSRGB(color.B)
+	X = 0.4124564*r + 0.3575761*g + 0.1804375*b
+	Y = 0.2126729*r + 0.7151522*g + 0.0721750*b
+	Z = 0.0193339*r + 0.1191920*g + 0.9503041*b
+	return X, Y, Z
+}
+
+// encodeSRGB applies the sRGB transfer function to a linear value in the range [0, 1]
+func encodeSRGB(v float64) uint8 {
+	if v <= 0.0031308 {
+		v *= 12.92
+	} else {
+		v = 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(v * math.MaxUint8))
+}
+
+// decodeSRGB reverses the sRGB transfer function, returning a linear value in the range [0, 1]
+func decodeSRGB(c uint8) float64 {
+	v := float64(c) / math.MaxUint8
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
@@ -0,0 +1,49 @@
+package color
+
+import (
+	ic "image/color"
+	"math"
+	"testing"
+)
+
+func TestKelvin(t *testing.T) {
+	tests := []struct {
+		kelvin float64
+		want   ic.RGBA
+	}{
+		{2200, ic.RGBA{R: 255, G: 150, B: 47, A: 255}},
+		{2700, ic.RGBA{R: 255, G: 173, B: 89, A: 255}},
+		{4000, ic.RGBA{R: 255, G: 211, B: 165, A: 255}},
+		{6500, ic.RGBA{R: 255, G: 249, B: 254, A: 255}},
+		{10000, ic.RGBA{R: 205, G: 217, B: 255, A: 255}},
+		// out of range temperatures are clamped
+		{1000, ic.RGBA{R: 255, G: 116, B: 0, A: 255}},
+		{40000, ic.RGBA{R: 165, G: 190, B: 255, A: 255}},
+	}
+
+	for _, test := range tests {
+		if result := Kelvin(test.kelvin); result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
+
+func TestCCT(t *testing.T) {
+	// McCamy's approximation is within a few percent over the range of tunable whites
+	for k := 1900.0; k <= 10000; k += 100 {
+		if result := CCT(Kelvin(k)); math.Abs(result-k)/k > 0.02 {
+			t.Errorf("Wanted %v, got: %v", k, result)
+		}
+	}
+	if result := CCT(ic.RGBA{}); result != 0 {
+		t.Errorf("Wanted %v, got: %v", 0, result)
+	}
+}
+
+func TestSRGBRoundTrip(t *testing.T) {
+	for v := 0; v <= math.MaxUint8; v++ {
+		if result := encodeSRGB(decodeSRGB(uint8(v))); result != uint8(v) {
+			t.Errorf("Wanted %v, got: %v", v, result)
+		}
+	}
+}
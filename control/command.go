@@ -0,0 +1,97 @@
+package control
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Command names accepted by the controller
+const (
+	LoadScene     = "load_scene"
+	SetBrightness = "set_brightness"
+	SetSpeed      = "set_speed"
+	Pause         = "pause"
+	Resume        = "resume"
+	Seek          = "seek"
+	GetState      = "get_state"
+)
+
+// Command is a single control message. Only the field used by the command may be set.
+type Command struct {
+	Command    string   `json:"command"`
+	Scene      *string  `json:"scene,omitempty"`
+	Brightness *int     `json:"brightness,omitempty"`
+	Speed      *float64 `json:"speed,omitempty"`
+	Step       *int     `json:"step,omitempty"`
+}
+
+// ParseCommand decodes a JSON command and validates it, unknown fields are rejected
+func ParseCommand(data []byte) (Command, error) {
+	var cmd Command
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cmd); err != nil {
+		return Command{}, fmt.Errorf("invalid command: %v", err)
+	}
+	// there must be exactly one JSON value
+	if decoder.More() {
+		return Command{}, errors.New("invalid command: unexpected data after the command")
+	}
+	if err := cmd.Validate(); err != nil {
+		return Command{}, err
+	}
+	return cmd, nil
+}
+
+// Validate checks that the command is known, that it has the field it needs and no others,
+// and that the field value is in range
+func (c *Command) Validate() error {
+	// the one field each command needs
+	var field string
+	switch c.Command {
+	case LoadScene:
+		field = "scene"
+		if c.Scene != nil && *c.Scene == "" {
+			return errors.New("scene must not be empty")
+		}
+	case SetBrightness:
+		field = "brightness"
+		if c.Brightness != nil && (*c.Brightness < 0 || *c.Brightness > math.MaxUint8) {
+			return fmt.Errorf("brightness must be between 0 and %d", math.MaxUint8)
+		}
+	case SetSpeed:
+		field = "speed"
+		if c.Speed != nil && (*c.Speed < 0 || math.IsInf(*c.Speed, 0) || math.IsNaN(*c.Speed)) {
+			return errors.New("speed must be a number of at least zero")
+		}
+	case Seek:
+		field = "step"
+		if c.Step != nil && *c.Step < 0 {
+			return errors.New("step must be at least zero")
+		}
+	case Pause, Resume, GetState:
+	case "":
+		return errors.New("command is required")
+	default:
+		return fmt.Errorf("unknown command %q", c.Command)
+	}
+	// compare the fields that are set with the one that is needed
+	set := map[string]bool{
+		"scene":      c.Scene != nil,
+		"brightness": c.Brightness != nil,
+		"speed":      c.Speed != nil,
+		"step":       c.Step != nil,
+	}
+	for name, isSet := range set {
+		if isSet && name != field {
+			return fmt.Errorf("%s does not take %s", c.Command, name)
+		}
+	}
+	if field != "" && !set[field] {
+		return fmt.Errorf("%s requires %s", c.Command, field)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package control
+
+import (
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		input string
+		valid bool
+	}{
+		{`{"command": "load_scene", "scene": "rainbow"}`, true},
+		{`{"command": "set_brightness", "brightness": 0}`, true},
+		{`{"command": "set_brightness", "brightness": 255}`, true},
+		{`{"command": "set_speed", "speed": 1.5}`, true},
+		{`{"command": "pause"}`, true},
+		{`{"command": "resume"}`, true},
+		{`{"command": "seek", "step": 10}`, true},
+		{`{"command": "get_state"}`, true},
+		{`{"command": "load_scene"}`, false},
+		{`{"command": "load_scene", "scene": ""}`, false},
+		{`{"command": "set_brightness", "brightness": 256}`, false},
+		{`{"command": "set_brightness", "brightness": -1}`, false},
+		{`{"command": "set_brightness", "brightness": 1.5}`, false},
+		{`{"command": "set_speed", "speed": -1}`, false},
+		{`{"command": "seek", "step": -1}`, false},
+		{`{"command": "pause", "step": 1}`, false},
+		{`{"command": "seek", "step": 1, "speed": 1}`, false},
+		{`{"command": "explode"}`, false},
+		{`{"scene": "rainbow"}`, false},
+		{`{"command": "pause", "color": "red"}`, false},
+		{`{"command": "pause"} {"command": "resume"}`, false},
+		{`not json`, false},
+	}
+
+	for _, test := range tests {
+		_, err := ParseCommand([]byte(test.input))
+		if test.valid && err != nil {
+			t.Errorf("%v: unexpected error: %v", test.input, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%v: Wanted an error", test.input)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package control
+
+import (
+	"fmt"
+	ic "image/color"
+	"sort"
+	"sync"
+
+	"github.com/gazek/color-blender/player"
+)
+
+// Scene creates a new Animation each time it is loaded
+type Scene func() player.Animation
+
+// State reports what a running player is doing
+type State struct {
+	Scene      string    `json:"scene"`
+	Brightness uint8     `json:"brightness"`
+	Speed      float64   `json:"speed"`
+	Paused     bool      `json:"paused"`
+	Step       int       `json:"step"`
+	Colors     []ic.RGBA `json:"colors"`
+	Scenes     []string  `json:"scenes"`
+}
+
+// Controller applies commands to a running Player
+type Controller struct {
+	mu     sync.Mutex
+	player *player.Player
+	scenes map[string]Scene
+	scene  string
+}
+
+// NewController creates a new Controller for the player
+func NewController(p *player.Player) *Controller {
+	return &Controller{player: p, scenes: map[string]Scene{}}
+}
+
+// AddScene registers a scene that can be loaded by name
+func (c *Controller) AddScene(name string, scene Scene) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.scenes[name] = scene
+}
+
+// Execute validates and applies the command, then reports the resulting state
+func (c *Controller) Execute(cmd Command) (State, error) {
+	if err := cmd.Validate(); err != nil {
+		return State{}, err
+	}
+	switch cmd.Command {
+	case LoadScene:
+		if err := c.LoadScene(*cmd.Scene); err != nil {
+			return State{}, err
+		}
+	case SetBrightness:
+		c.player.SetBrightness(uint8(*cmd.Brightness))
+	case SetSpeed:
+		c.player.SetSpeed(*cmd.Speed)
+	case Pause:
+		c.player.Pause()
+	case Resume:
+		c.player.Resume()
+	case Seek:
+		c.player.Seek(*cmd.Step)
+	}
+	return c.State(), nil
+}
+
+// LoadScene replaces the player's animation with a new instance of the named scene
+func (c *Controller) LoadScene(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	scene, ok := c.scenes[name]
+	if !ok {
+		return fmt.Errorf("unknown scene %q", name)
+	}
+	c.player.SetAnimation(scene())
+	c.scene = name
+	return nil
+}
+
+// hasScene reports whether a scene is registered under the name
+func (c *Controller) hasScene(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.scenes[name]
+	return ok
+}
+
+// setAnimation replaces the player's animation with one that isn't registered as a scene
+func (c *Controller) setAnimation(name string, animation player.Animation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.player.SetAnimation(animation)
+	c.scene = name
+}
+
+// State reports the current state of the player
+func (c *Controller) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := State{
+		Scene:      c.scene,
+		Brightness: c.player.GetBrightness(),
+		Speed:      c.player.GetSpeed(),
+		Paused:     c.player.IsPaused(),
+		Step:       c.player.GetPosition(),
+		Colors:     []ic.RGBA{},
+		Scenes:     []string{},
+	}
+	for _, pixel := range c.player.LastFrame() {
+		s.Colors = append(s.Colors, pixel.GetColor())
+	}
+	for name := range c.scenes {
+		s.Scenes = append(s.Scenes, name)
+	}
+	sort.Strings(s.Scenes)
+	return s
+}
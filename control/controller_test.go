@@ -0,0 +1,139 @@
+package control
+
+import (
+	ic "image/color"
+	"testing"
+	"time"
+
+	"github.com/gazek/color-blender/blender"
+	"github.com/gazek/color-blender/color"
+	"github.com/gazek/color-blender/player"
+)
+
+// solidAnimation renders a single pixel of one color and counts its steps
+type solidAnimation struct {
+	color ic.RGBA
+	step  int
+}
+
+func (a *solidAnimation) ResetStep() {
+	a.step = 0
+}
+
+func (a *solidAnimation) AdvanceStep(numSteps int) {
+	a.step += numSteps
+}
+
+func (a *solidAnimation) Frame() []color.Color {
+	return []color.Color{*color.NewColor(a.color)}
+}
+
+// fakeClock is a Clock that only moves when told to
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// newTestController creates a controller with red and green scenes and the red scene loaded
+func newTestController(t *testing.T) (*Controller, *player.Player, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	p, err := player.NewPlayer(&solidAnimation{}, nil, 10, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p.SetClock(clock)
+	c := NewController(p)
+	c.AddScene("red", func() player.Animation { return &solidAnimation{color: ic.RGBA{R: 255, A: 255}} })
+	c.AddScene("green", func() player.Animation { return &solidAnimation{color: ic.RGBA{G: 255, A: 255}} })
+	if err := c.LoadScene("red"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return c, p, clock
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestExecuteBrightnessCurve(t *testing.T) {
+	c, p, _ := newTestController(t)
+	// the player's dimming curve applies to brightness set through the control API
+	p.SetDimmingCurve(blender.CIELightnessCurve)
+	if _, err := c.Execute(Command{Command: SetBrightness, Brightness: intPtr(128)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p.Tick()
+	want := ic.RGBA{R: 255, A: 47}
+	if state := c.State(); len(state.Colors) != 1 || state.Colors[0] != want {
+		t.Errorf("Wanted %v, got: %v", want, state.Colors)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	c, p, clock := newTestController(t)
+	scene := "green"
+	speed := 2.0
+	commands := []Command{
+		{Command: LoadScene, Scene: &scene},
+		{Command: SetBrightness, Brightness: intPtr(128)},
+		{Command: SetSpeed, Speed: &speed},
+		{Command: Pause},
+	}
+	for _, cmd := range commands {
+		if _, err := c.Execute(cmd); err != nil {
+			t.Fatalf("%v: unexpected error: %v", cmd.Command, err)
+		}
+	}
+	p.Tick()
+	clock.Sleep(time.Second)
+	p.Tick()
+	state := c.State()
+	if state.Scene != "green" || state.Brightness != 128 || state.Speed != 2 || !state.Paused || state.Step != 0 {
+		t.Errorf("Unexpected state: %+v", state)
+	}
+	want := ic.RGBA{G: 255, A: 128}
+	if len(state.Colors) != 1 || state.Colors[0] != want {
+		t.Errorf("Wanted %v, got: %v", want, state.Colors)
+	}
+	if len(state.Scenes) != 2 || state.Scenes[0] != "green" || state.Scenes[1] != "red" {
+		t.Errorf("Wanted %v, got: %v", []string{"green", "red"}, state.Scenes)
+	}
+	// resume and jump
+	if _, err := c.Execute(Command{Command: Resume}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	state, err := c.Execute(Command{Command: Seek, Step: intPtr(30)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state.Paused || state.Step != 30 {
+		t.Errorf("Unexpected state: %+v", state)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	c, _, _ := newTestController(t)
+	scene := "blue"
+	tests := []Command{
+		{Command: LoadScene, Scene: &scene},
+		{Command: SetBrightness},
+		{Command: "dance"},
+	}
+
+	for _, test := range tests {
+		if _, err := c.Execute(test); err == nil {
+			t.Errorf("%v: Wanted an error", test.Command)
+		}
+	}
+	// the failed load should leave the scene alone
+	if state := c.State(); state.Scene != "red" {
+		t.Errorf("Wanted %v, got: %v", "red", state.Scene)
+	}
+}
@@ -0,0 +1,62 @@
+package control
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// maxCommandLen limits the size of a command request body
+const maxCommandLen = 4096
+
+// errorResponse is the body returned when a request fails
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns an http.Handler serving the control API:
+// GET /state reports the state and POST /command executes a command and reports the new state
+func (c *Controller) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", c.handleState)
+	mux.HandleFunc("/command", c.handleCommand)
+	return mux
+}
+
+func (c *Controller) handleState(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "state only supports GET"})
+		return
+	}
+	writeJSON(w, http.StatusOK, c.State())
+}
+
+func (c *Controller) handleCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "command only supports POST"})
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCommandLen))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	cmd, err := ParseCommand(body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	state, err := c.Execute(cmd)
+	if err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+// writeJSON writes the value as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,55 @@
+package control
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	c, _, _ := newTestController(t)
+	server := httptest.NewServer(c.Handler())
+	defer server.Close()
+	tests := []struct {
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantScene  string
+	}{
+		{http.MethodGet, "/state", "", http.StatusOK, "red"},
+		{http.MethodPost, "/command", `{"command": "load_scene", "scene": "green"}`, http.StatusOK, "green"},
+		{http.MethodPost, "/command", `{"command": "load_scene", "scene": "blue"}`, http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/command", `{"command": "set_brightness", "brightness": 300}`, http.StatusBadRequest, ""},
+		{http.MethodPost, "/state", "", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "/command", "", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, server.URL+test.path, strings.NewReader(test.body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.StatusCode != test.wantStatus {
+			t.Errorf("%v %v %v: Wanted status %v, got: %v", test.method, test.path, test.body, test.wantStatus, resp.StatusCode)
+		}
+		if test.wantStatus == http.StatusOK {
+			var state State
+			if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if state.Scene != test.wantScene {
+				t.Errorf("Wanted scene %v, got: %v", test.wantScene, state.Scene)
+			}
+		} else {
+			var e errorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error == "" {
+				t.Errorf("Wanted an error message, got: %v, %v", e, err)
+			}
+		}
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,28 @@
+package control
+
+import (
+	"encoding/json"
+)
+
+// MQTTClient is the part of an MQTT client the controller needs, so any client library can be adapted to it
+type MQTTClient interface {
+	Subscribe(topic string, handler func(topic string, payload []byte)) error
+	Publish(topic string, payload []byte) error
+}
+
+// ServeMQTT executes the commands published to the command topic and publishes the resulting
+// state, or an error, to the state topic
+func (c *Controller) ServeMQTT(client MQTTClient, commandTopic string, stateTopic string) error {
+	return client.Subscribe(commandTopic, func(topic string, payload []byte) {
+		var response interface{}
+		cmd, err := ParseCommand(payload)
+		if err == nil {
+			response, err = c.Execute(cmd)
+		}
+		if err != nil {
+			response = errorResponse{Error: err.Error()}
+		}
+		data, _ := json.Marshal(response)
+		client.Publish(stateTopic, data)
+	})
+}
@@ -0,0 +1,54 @@
+package control
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// broker is an in-process stand-in for an MQTT broker and client
+type broker struct {
+	handlers  map[string]func(topic string, payload []byte)
+	published map[string][][]byte
+}
+
+func newBroker() *broker {
+	return &broker{
+		handlers:  map[string]func(topic string, payload []byte){},
+		published: map[string][][]byte{},
+	}
+}
+
+func (b *broker) Subscribe(topic string, handler func(topic string, payload []byte)) error {
+	b.handlers[topic] = handler
+	return nil
+}
+
+func (b *broker) Publish(topic string, payload []byte) error {
+	b.published[topic] = append(b.published[topic], payload)
+	if handler, ok := b.handlers[topic]; ok {
+		handler(topic, payload)
+	}
+	return nil
+}
+
+func TestServeMQTT(t *testing.T) {
+	c, _, _ := newTestController(t)
+	b := newBroker()
+	if err := c.ServeMQTT(b, "lights/set", "lights/state"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b.Publish("lights/set", []byte(`{"command": "set_brightness", "brightness": 42}`))
+	b.Publish("lights/set", []byte(`{"command": "set_brightness"}`))
+	responses := b.published["lights/state"]
+	if len(responses) != 2 {
+		t.Fatalf("Wanted %v responses, got: %v", 2, len(responses))
+	}
+	var state State
+	if err := json.Unmarshal(responses[0], &state); err != nil || state.Brightness != 42 {
+		t.Errorf("Wanted brightness %v, got: %v, %v", 42, state.Brightness, err)
+	}
+	var e errorResponse
+	if err := json.Unmarshal(responses[1], &e); err != nil || e.Error == "" {
+		t.Errorf("Wanted an error message, got: %v, %v", e, err)
+	}
+}
@@ -0,0 +1,294 @@
+package control
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	ic "image/color"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+
+	"github.com/gazek/color-blender/blender"
+	"github.com/gazek/color-blender/spatial"
+	"github.com/gazek/color-blender/transfunc"
+)
+
+// wledSolidScene is the scene name reported while WLED effect zero shows the segment colors
+const wledSolidScene = "wled_solid"
+
+// wledDefaultSpeed is the WLED effect speed that plays a scene at its normal step rate
+const wledDefaultSpeed = 128
+
+// wledSpeed converts a WLED effect speed into a playback speed. Like WLED, zero is the slowest speed
+// rather than a stop.
+func wledSpeed(sx int) float64 {
+	return float64(sx+1) / (wledDefaultSpeed + 1)
+}
+
+// WLEDSegment is the part of a WLED segment that is supported
+type WLEDSegment struct {
+	Colors [][]int `json:"col"`
+	Effect int     `json:"fx"`
+	Speed  int     `json:"sx"`
+}
+
+// WLEDState is the part of the WLED /json/state document that is supported
+type WLEDState struct {
+	On         bool          `json:"on"`
+	Brightness int           `json:"bri"`
+	Segments   []WLEDSegment `json:"seg"`
+}
+
+// wledUpdate is a partial state, only the fields that are present are changed
+type wledUpdate struct {
+	On         *bool               `json:"on"`
+	Brightness *int                `json:"bri"`
+	Segments   []wledSegmentUpdate `json:"seg"`
+}
+
+type wledSegmentUpdate struct {
+	Colors [][]int `json:"col"`
+	Effect *int    `json:"fx"`
+	Speed  *int    `json:"sx"`
+}
+
+// WLED maps the WLED JSON API onto a Controller. The segment colors become the ColorFunc anchors
+// of effect zero, the brightness becomes the master brightness, the other effect ids load named
+// scenes and the effect speed scales the step rate.
+type WLED struct {
+	mu         sync.Mutex
+	controller *Controller
+	numPixels  int
+	// transitionSteps is the period of each ColorFunc between two segment colors
+	transitionSteps int
+	effects         map[int]string
+	state           WLEDState
+	// applied is set once the state has been pushed to the controller
+	applied bool
+}
+
+// NewWLED creates a new WLED adapter for a strip of numPixels pixels.
+// Effect zero cycles through the segment colors, taking transitionSteps steps between each of them.
+func NewWLED(c *Controller, numPixels int, transitionSteps int) (*WLED, error) {
+	if transitionSteps <= 0 {
+		return nil, errors.New("transition steps must be greater than zero")
+	}
+	return &WLED{
+		controller:      c,
+		numPixels:       numPixels,
+		transitionSteps: transitionSteps,
+		effects:         map[int]string{},
+		state: WLEDState{
+			On:         true,
+			Brightness: math.MaxUint8,
+			Segments: []WLEDSegment{{
+				Colors: [][]int{{math.MaxUint8, 0, 0}, {0, 0, 0}, {0, 0, 0}},
+				Speed:  wledDefaultSpeed,
+			}},
+		},
+	}, nil
+}
+
+// SetEffect maps a WLED effect id onto a scene registered with the controller
+func (w *WLED) SetEffect(id int, scene string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.effects[id] = scene
+}
+
+// State returns the current WLED state
+func (w *WLED) State() WLEDState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.copyState()
+}
+
+// Update validates the partial state in the JSON document and applies it
+func (w *WLED) Update(data []byte) (WLEDState, error) {
+	var u wledUpdate
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&u); err != nil {
+		return WLEDState{}, fmt.Errorf("invalid state: %v", err)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// build the new state before changing anything so a bad update changes nothing
+	next := w.copyState()
+	if u.On != nil {
+		next.On = *u.On
+	}
+	if u.Brightness != nil {
+		next.Brightness = *u.Brightness
+	}
+	if len(u.Segments) > 0 {
+		seg := &next.Segments[0]
+		if u.Segments[0].Colors != nil {
+			seg.Colors = u.Segments[0].Colors
+		}
+		if u.Segments[0].Effect != nil {
+			seg.Effect = *u.Segments[0].Effect
+		}
+		if u.Segments[0].Speed != nil {
+			seg.Speed = *u.Segments[0].Speed
+		}
+	}
+	if err := w.validate(next); err != nil {
+		return WLEDState{}, err
+	}
+	if err := w.apply(next); err != nil {
+		return WLEDState{}, err
+	}
+	return w.copyState(), nil
+}
+
+// Handler returns an http.Handler serving GET and POST on /json/state
+func (w *WLED) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json/state", func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(rw, http.StatusOK, w.State())
+		case http.MethodPost:
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxCommandLen))
+			if err != nil {
+				writeJSON(rw, http.StatusBadRequest, errorResponse{Error: err.Error()})
+				return
+			}
+			state, err := w.Update(body)
+			if err != nil {
+				writeJSON(rw, http.StatusBadRequest, errorResponse{Error: err.Error()})
+				return
+			}
+			writeJSON(rw, http.StatusOK, state)
+		default:
+			writeJSON(rw, http.StatusMethodNotAllowed, errorResponse{Error: "state only supports GET and POST"})
+		}
+	})
+	return mux
+}
+
+// validate checks that the state values are in range and that the effect maps to a registered scene
+func (w *WLED) validate(s WLEDState) error {
+	if s.Brightness < 0 || s.Brightness > math.MaxUint8 {
+		return fmt.Errorf("bri must be between 0 and %d", math.MaxUint8)
+	}
+	seg := s.Segments[0]
+	if len(seg.Colors) == 0 {
+		return errors.New("col must have at least one color")
+	}
+	for i, c := range seg.Colors {
+		// WLED sends RGB or RGBW
+		if len(c) != 3 && len(c) != 4 {
+			return fmt.Errorf("col %d must have 3 or 4 values", i)
+		}
+		for _, v := range c {
+			if v < 0 || v > math.MaxUint8 {
+				return fmt.Errorf("col %d values must be between 0 and %d", i, math.MaxUint8)
+			}
+		}
+	}
+	if seg.Effect != 0 {
+		scene, ok := w.effects[seg.Effect]
+		if !ok {
+			return fmt.Errorf("unknown fx %d", seg.Effect)
+		}
+		if !w.controller.hasScene(scene) {
+			return fmt.Errorf("fx %d maps to unknown scene %q", seg.Effect, scene)
+		}
+	}
+	if seg.Speed < 0 || seg.Speed > math.MaxUint8 {
+		return fmt.Errorf("sx must be between 0 and %d", math.MaxUint8)
+	}
+	return nil
+}
+
+// apply pushes the changes between the current and the next state to the controller.
+// The state is only updated once the animation has been loaded.
+func (w *WLED) apply(next WLEDState) error {
+	prev := w.state
+	seg := next.Segments[0]
+	// only restart the animation when the effect or its colors changed
+	if !w.applied || seg.Effect != prev.Segments[0].Effect || !sameColors(seg.Colors, prev.Segments[0].Colors) {
+		if seg.Effect == 0 {
+			w.controller.setAnimation(wledSolidScene, spatial.NewRenderer(w.colorBlender(seg.Colors), w.numPixels, nil))
+		} else if err := w.controller.LoadScene(w.effects[seg.Effect]); err != nil {
+			return err
+		}
+		w.applied = true
+	}
+	w.state = next
+	// turning off keeps the brightness so turning on again restores it
+	brightness := uint8(next.Brightness)
+	if !next.On {
+		brightness = 0
+	}
+	w.controller.player.SetBrightness(brightness)
+	w.controller.player.SetSpeed(wledSpeed(seg.Speed))
+	return nil
+}
+
+// colorBlender creates a blender that cycles through the colors, it holds a single color steady
+func (w *WLED) colorBlender(colors [][]int) *blender.Blender {
+	anchors := make([]ic.RGBA, len(colors))
+	for i := range colors {
+		anchors[i] = wledColor(colors[i])
+	}
+	// WLED always sends three colors, trailing black ones are unused
+	for len(anchors) > 1 && anchors[len(anchors)-1] == (ic.RGBA{}) {
+		anchors = anchors[:len(anchors)-1]
+	}
+	b := &blender.Blender{}
+	for i := range anchors {
+		next := anchors[(i+1)%len(anchors)]
+		b.AppendColorFunc(transfunc.NewColorFunc(anchors[i], next, transfunc.AllAtOnce, func(x float32) float32 { return x }, w.transitionSteps, []float32{0, 1}))
+	}
+	return b
+}
+
+// copyState returns a deep copy of the state so callers can't change it
+func (w *WLED) copyState() WLEDState {
+	s := w.state
+	s.Segments = make([]WLEDSegment, len(w.state.Segments))
+	copy(s.Segments, w.state.Segments)
+	for i := range s.Segments {
+		colors := make([][]int, len(s.Segments[i].Colors))
+		for c := range colors {
+			colors[c] = append([]int(nil), s.Segments[i].Colors[c]...)
+		}
+		s.Segments[i].Colors = colors
+	}
+	return s
+}
+
+// wledColor converts a WLED RGB or RGBW color, the white channel is added to red, green and blue
+func wledColor(c []int) ic.RGBA {
+	w := 0
+	if len(c) == 4 {
+		w = c[3]
+	}
+	return ic.RGBA{
+		R: uint8(math.Min(float64(c[0]+w), math.MaxUint8)),
+		G: uint8(math.Min(float64(c[1]+w), math.MaxUint8)),
+		B: uint8(math.Min(float64(c[2]+w), math.MaxUint8)),
+	}
+}
+
+// sameColors reports whether two color lists are equal
+func sameColors(a [][]int, b [][]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
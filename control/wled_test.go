@@ -0,0 +1,187 @@
+package control
+
+import (
+	"encoding/json"
+	ic "image/color"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWLEDUpdate(t *testing.T) {
+	c, p, clock := newTestController(t)
+	w, _ := NewWLED(c, 2, 10)
+	w.SetEffect(5, "green")
+	// two colors blend back and forth, trailing black colors are ignored
+	state, err := w.Update([]byte(`{"on": true, "bri": 128, "seg": [{"col": [[255, 0, 0], [0, 0, 255], [0, 0, 0]], "sx": 255}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !state.On || state.Brightness != 128 || state.Segments[0].Speed != 255 {
+		t.Errorf("Unexpected state: %+v", state)
+	}
+	if c.State().Scene != wledSolidScene {
+		t.Errorf("Wanted scene %v, got: %v", wledSolidScene, c.State().Scene)
+	}
+	if p.GetBrightness() != 128 {
+		t.Errorf("Wanted brightness %v, got: %v", 128, p.GetBrightness())
+	}
+	if speed := p.GetSpeed(); speed != 256.0/129 {
+		t.Errorf("Wanted speed %v, got: %v", 256.0/129, speed)
+	}
+	p.Tick()
+	frame := p.LastFrame()
+	want := ic.RGBA{R: 255, A: 128}
+	if len(frame) != 2 || frame[0].GetColor() != want || frame[1].GetColor() != want {
+		t.Errorf("Wanted 2 pixels of %v, got: %v", want, frame)
+	}
+	// halfway through the first transition at almost double speed
+	clock.Sleep(260 * time.Millisecond)
+	p.Tick()
+	if result := p.LastFrame()[0].GetColor(); result.R != 128 || result.B != 127 {
+		t.Errorf("Wanted a blend of red and blue, got: %v", result)
+	}
+	// turning off keeps the brightness
+	state, _ = w.Update([]byte(`{"on": false}`))
+	if state.Brightness != 128 || p.GetBrightness() != 0 {
+		t.Errorf("Wanted bri %v and player brightness %v, got: %v and %v", 128, 0, state.Brightness, p.GetBrightness())
+	}
+	w.Update([]byte(`{"on": true}`))
+	if p.GetBrightness() != 128 {
+		t.Errorf("Wanted brightness %v, got: %v", 128, p.GetBrightness())
+	}
+	// effects load named scenes
+	w.Update([]byte(`{"seg": [{"fx": 5}]}`))
+	if c.State().Scene != "green" {
+		t.Errorf("Wanted scene %v, got: %v", "green", c.State().Scene)
+	}
+}
+
+func TestWLEDSpeed(t *testing.T) {
+	tests := []struct {
+		sx   int
+		want float64
+	}{
+		{0, 1.0 / 129},
+		{wledDefaultSpeed, 1},
+		{255, 256.0 / 129},
+	}
+
+	for _, test := range tests {
+		if result := wledSpeed(test.sx); result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+	// the slowest speed still moves
+	c, p, _ := newTestController(t)
+	w, _ := NewWLED(c, 1, 10)
+	if _, err := w.Update([]byte(`{"seg": [{"sx": 0}]}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.GetSpeed() <= 0 {
+		t.Errorf("Wanted a positive speed, got: %v", p.GetSpeed())
+	}
+}
+
+func TestNewWLEDErrors(t *testing.T) {
+	c, _, _ := newTestController(t)
+	for _, steps := range []int{0, -1} {
+		if _, err := NewWLED(c, 1, steps); err == nil {
+			t.Errorf("Wanted an error for %v transition steps", steps)
+		}
+	}
+}
+
+func TestWLEDUpdateErrors(t *testing.T) {
+	c, _, _ := newTestController(t)
+	w, _ := NewWLED(c, 1, 10)
+	// an effect mapped to a scene that was never registered
+	w.SetEffect(8, "missing")
+	tests := []string{
+		`{"bri": 256}`,
+		`{"seg": [{"col": [[255, 0]]}]}`,
+		`{"seg": [{"col": [[256, 0, 0]]}]}`,
+		`{"seg": [{"col": []}]}`,
+		`{"seg": [{"fx": 7}]}`,
+		`{"seg": [{"fx": 8}]}`,
+		`{"bri": 10, "seg": [{"fx": 8}]}`,
+		`{"seg": [{"sx": -1}]}`,
+		`{`,
+	}
+
+	for _, test := range tests {
+		if _, err := w.Update([]byte(test)); err == nil {
+			t.Errorf("%v: Wanted an error", test)
+		}
+	}
+	// nothing should have changed
+	if state := w.State(); state.Brightness != 255 || state.Segments[0].Effect != 0 {
+		t.Errorf("Unexpected state: %+v", state)
+	}
+	if c.State().Scene != "red" {
+		t.Errorf("Wanted scene %v, got: %v", "red", c.State().Scene)
+	}
+	// a scene that fails to load leaves the state alone even if it got past validation
+	next := w.State()
+	next.Brightness = 10
+	next.Segments[0].Effect = 8
+	if err := w.apply(next); err == nil {
+		t.Error("Wanted an error for the missing scene")
+	}
+	if state := w.State(); state.Brightness != 255 || state.Segments[0].Effect != 0 {
+		t.Errorf("Unexpected state: %+v", state)
+	}
+}
+
+func TestWLEDColor(t *testing.T) {
+	tests := []struct {
+		color []int
+		want  ic.RGBA
+	}{
+		{[]int{1, 2, 3}, ic.RGBA{R: 1, G: 2, B: 3}},
+		{[]int{1, 2, 3, 10}, ic.RGBA{R: 11, G: 12, B: 13}},
+		{[]int{250, 2, 3, 10}, ic.RGBA{R: 255, G: 12, B: 13}},
+	}
+
+	for _, test := range tests {
+		if result := wledColor(test.color); result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
+
+func TestWLEDHandler(t *testing.T) {
+	c, _, _ := newTestController(t)
+	w, _ := NewWLED(c, 1, 10)
+	server := httptest.NewServer(w.Handler())
+	defer server.Close()
+	resp, err := http.Post(server.URL+"/json/state", "application/json", strings.NewReader(`{"bri": 10}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var state WLEDState
+	json.NewDecoder(resp.Body).Decode(&state)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || state.Brightness != 10 {
+		t.Errorf("Wanted status %v and bri %v, got: %v and %v", http.StatusOK, 10, resp.StatusCode, state.Brightness)
+	}
+	resp, _ = http.Get(server.URL + "/json/state")
+	json.NewDecoder(resp.Body).Decode(&state)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || state.Brightness != 10 || len(state.Segments) != 1 {
+		t.Errorf("Unexpected response: %v %+v", resp.StatusCode, state)
+	}
+	resp, _ = http.Post(server.URL+"/json/state", "application/json", strings.NewReader(`{"bri": -1}`))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Wanted status %v, got: %v", http.StatusBadRequest, resp.StatusCode)
+	}
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/json/state", nil)
+	resp, _ = http.DefaultClient.Do(req)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Wanted status %v, got: %v", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+}
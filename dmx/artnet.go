@@ -0,0 +1,80 @@
+package dmx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// ArtNetPort is the UDP port used by Art-Net
+const ArtNetPort = 6454
+
+// ArtNetMaxUniverse is the largest 15 bit port address, Art-Net universes start at zero
+const ArtNetMaxUniverse = 0x7fff
+
+// artDmxHeaderLen is the length of an ArtDmx packet without the DMX data
+const artDmxHeaderLen = 18
+
+// ArtDmxPacket builds an Art-Net ArtDmx packet carrying the data for one 15 bit port address,
+// which must be no more than ArtNetMaxUniverse
+func ArtDmxPacket(sequence uint8, universe uint16, data []byte) []byte {
+	// the data length must be even
+	length := len(data)
+	if length%2 == 1 {
+		length++
+	}
+	p := make([]byte, artDmxHeaderLen+length)
+	copy(p[0:8], "Art-Net\x00")
+	binary.LittleEndian.PutUint16(p[8:], 0x5000)
+	binary.BigEndian.PutUint16(p[10:], 14)
+	p[12] = sequence
+	p[13] = 0
+	// sub-net and universe in the low byte, net in the high byte
+	p[14] = byte(universe)
+	p[15] = byte(universe>>8) & 0x7f
+	binary.BigEndian.PutUint16(p[16:], uint16(length))
+	copy(p[artDmxHeaderLen:], data)
+	return p
+}
+
+// ArtNetSender sends frames as ArtDmx packets, one packet per universe
+type ArtNetSender struct {
+	conn      net.PacketConn
+	addr      net.Addr
+	mapping   Mapping
+	sequence  uint8
+	universes []Universe
+}
+
+// NewArtNetSender creates a new ArtNetSender that sends to the node (or broadcast) address
+func NewArtNetSender(conn net.PacketConn, addr net.Addr, mapping Mapping) *ArtNetSender {
+	return &ArtNetSender{conn: conn, addr: addr, mapping: mapping}
+}
+
+// WriteFrame maps the frame onto universes and sends them
+func (s *ArtNetSender) WriteFrame(frame []color.Color) error {
+	var err error
+	s.universes, err = s.mapping.Map(s.universes, frame)
+	if err != nil {
+		return err
+	}
+	// check every universe before sending any of them
+	for _, u := range s.universes {
+		if u.Number > ArtNetMaxUniverse {
+			return fmt.Errorf("Art-Net universe %d is outside 0-%d", u.Number, ArtNetMaxUniverse)
+		}
+	}
+	// sequence zero disables reordering on the receiver, so skip it
+	s.sequence++
+	if s.sequence == 0 {
+		s.sequence = 1
+	}
+	for _, u := range s.universes {
+		if _, err := s.conn.WriteTo(ArtDmxPacket(s.sequence, u.Number, u.Data), s.addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package dmx
+
+import (
+	"bytes"
+	ic "image/color"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestArtDmxPacket(t *testing.T) {
+	tests := []struct {
+		name     string
+		universe uint16
+		data     []byte
+		want     []byte
+	}{
+		{"even", 0x0123, []byte{1, 2}, []byte{'A', 'r', 't', '-', 'N', 'e', 't', 0, 0x00, 0x50, 0, 14, 5, 0, 0x23, 0x01, 0, 2, 1, 2}},
+		{"odd", 0x7fff, []byte{1, 2, 3}, []byte{'A', 'r', 't', '-', 'N', 'e', 't', 0, 0x00, 0x50, 0, 14, 5, 0, 0xff, 0x7f, 0, 4, 1, 2, 3, 0}},
+	}
+
+	for _, test := range tests {
+		if result := ArtDmxPacket(5, test.universe, test.data); !bytes.Equal(result, test.want) {
+			t.Errorf("%v: Wanted % x, got: % x", test.name, test.want, result)
+		}
+	}
+}
+
+func TestArtNetSender(t *testing.T) {
+	// the listener stands in for the receiving node
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Unable to listen on loopback: %v", err)
+	}
+	defer listener.Close()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+	s := NewArtNetSender(conn, listener.LocalAddr(), NewMapping(0))
+	if err := s.WriteFrame(solidFrame(171, ic.RGBA{G: 7, A: 255})); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []struct {
+		universe byte
+		length   int
+	}{
+		{0, 510},
+		{1, 4},
+	}
+	buf := make([]byte, 1024)
+	for i := range want {
+		listener.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := listener.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if buf[14] != want[i].universe {
+			t.Errorf("Packet %v: Wanted universe %v, got: %v", i, want[i].universe, buf[14])
+		}
+		if buf[12] != 1 {
+			t.Errorf("Packet %v: Wanted sequence %v, got: %v", i, 1, buf[12])
+		}
+		if n-artDmxHeaderLen != want[i].length || buf[artDmxHeaderLen+1] != 7 {
+			t.Errorf("Packet %v: Wanted %v channels with green 7, got: %v with %v", i, want[i].length, n-artDmxHeaderLen, buf[artDmxHeaderLen+1])
+		}
+	}
+}
+
+func TestArtNetSenderUniverseRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		universe  uint16
+		numPixels int
+		wantErr   bool
+	}{
+		{"zero", 0, 1, false},
+		{"last", 0x7fff, 170, false},
+		{"past the last", 0x8000, 1, true},
+		{"runs past the last", 0x7fff, 171, true},
+	}
+
+	for _, test := range tests {
+		conn := &countingConn{}
+		s := NewArtNetSender(conn, nil, NewMapping(test.universe))
+		err := s.WriteFrame(solidFrame(test.numPixels, ic.RGBA{R: 9, A: 255}))
+		if (err != nil) != test.wantErr {
+			t.Errorf("%v: Wanted error %v, got: %v", test.name, test.wantErr, err)
+		}
+		// nothing is sent for a frame with a bad universe
+		if test.wantErr && conn.packets != 0 {
+			t.Errorf("%v: Wanted no packets, got: %v", test.name, conn.packets)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package dmx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// E131Port is the UDP port used by E1.31 (sACN)
+const E131Port = 5568
+
+// The range of universe numbers E1.31 allows for data, unlike Art-Net there is no universe zero
+const (
+	E131MinUniverse = 1
+	E131MaxUniverse = 63999
+)
+
+// e131HeaderLen is the length of an E1.31 data packet without the DMX data
+const e131HeaderLen = 126
+
+// E131Packet builds an E1.31 data packet carrying the data for one universe
+func E131Packet(cid [16]byte, sourceName string, priority uint8, sequence uint8, universe uint16, data []byte) []byte {
+	p := make([]byte, e131HeaderLen+len(data))
+	// root layer
+	binary.BigEndian.PutUint16(p[0:], 0x0010)
+	binary.BigEndian.PutUint16(p[2:], 0x0000)
+	copy(p[4:16], "ASC-E1.17\x00\x00\x00")
+	binary.BigEndian.PutUint16(p[16:], flagsAndLength(len(p)-16))
+	binary.BigEndian.PutUint32(p[18:], 0x00000004)
+	copy(p[22:38], cid[:])
+	// framing layer
+	binary.BigEndian.PutUint16(p[38:], flagsAndLength(len(p)-38))
+	binary.BigEndian.PutUint32(p[40:], 0x00000002)
+	// leave room for the null terminator
+	name := sourceName
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	copy(p[44:108], name)
+	p[108] = priority
+	binary.BigEndian.PutUint16(p[109:], 0)
+	p[111] = sequence
+	p[112] = 0
+	binary.BigEndian.PutUint16(p[113:], universe)
+	// DMP layer
+	binary.BigEndian.PutUint16(p[115:], flagsAndLength(len(p)-115))
+	p[117] = 0x02
+	p[118] = 0xa1
+	binary.BigEndian.PutUint16(p[119:], 0x0000)
+	binary.BigEndian.PutUint16(p[121:], 0x0001)
+	binary.BigEndian.PutUint16(p[123:], uint16(len(data)+1))
+	// DMX512 start code followed by the channel data
+	p[125] = 0x00
+	copy(p[e131HeaderLen:], data)
+	return p
+}
+
+// flagsAndLength combines the protocol flags with a PDU length
+func flagsAndLength(length int) uint16 {
+	return 0x7000 | uint16(length&0x0fff)
+}
+
+// E131MulticastAddr returns the multicast address for the universe, which must be within
+// E131MinUniverse and E131MaxUniverse
+func E131MulticastAddr(universe uint16) *net.UDPAddr {
+	return &net.UDPAddr{
+		IP:   net.IPv4(239, 255, byte(universe>>8), byte(universe)),
+		Port: E131Port,
+	}
+}
+
+// E131Sender sends frames as E1.31 packets, one packet per universe
+type E131Sender struct {
+	conn       net.PacketConn
+	addr       net.Addr
+	mapping    Mapping
+	cid        [16]byte
+	sourceName string
+	priority   uint8
+	sequence   map[uint16]uint8
+	universes  []Universe
+}
+
+// NewE131Sender creates a new E131Sender. A nil addr sends each universe to its multicast address.
+func NewE131Sender(conn net.PacketConn, addr net.Addr, mapping Mapping, cid [16]byte, sourceName string) *E131Sender {
+	return &E131Sender{
+		conn:       conn,
+		addr:       addr,
+		mapping:    mapping,
+		cid:        cid,
+		sourceName: sourceName,
+		priority:   100,
+		sequence:   map[uint16]uint8{},
+	}
+}
+
+// SetPriority sets the priority (0-200) sent with each packet
+func (s *E131Sender) SetPriority(priority uint8) {
+	s.priority = priority
+}
+
+// WriteFrame maps the frame onto universes and sends them
+func (s *E131Sender) WriteFrame(frame []color.Color) error {
+	var err error
+	s.universes, err = s.mapping.Map(s.universes, frame)
+	if err != nil {
+		return err
+	}
+	// check every universe before sending any of them
+	for _, u := range s.universes {
+		if u.Number < E131MinUniverse || u.Number > E131MaxUniverse {
+			return fmt.Errorf("E1.31 universe %d is outside %d-%d", u.Number, E131MinUniverse, E131MaxUniverse)
+		}
+	}
+	for _, u := range s.universes {
+		// each universe has its own sequence number
+		seq := s.sequence[u.Number]
+		s.sequence[u.Number] = seq + 1
+		addr := s.addr
+		if addr == nil {
+			addr = E131MulticastAddr(u.Number)
+		}
+		packet := E131Packet(s.cid, s.sourceName, s.priority, seq, u.Number, u.Data)
+		if _, err := s.conn.WriteTo(packet, addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package dmx
+
+import (
+	"bytes"
+	"encoding/binary"
+	ic "image/color"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestE131Packet(t *testing.T) {
+	cid := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	data := []byte{10, 20, 30}
+	p := E131Packet(cid, "blender", 150, 7, 0x0102, data)
+	if len(p) != 129 {
+		t.Fatalf("Wanted %v bytes, got: %v", 129, len(p))
+	}
+	tests := []struct {
+		name   string
+		offset int
+		want   []byte
+	}{
+		{"preamble", 0, []byte{0x00, 0x10, 0x00, 0x00}},
+		{"packet id", 4, []byte("ASC-E1.17\x00\x00\x00")},
+		{"root flags and length", 16, []byte{0x70, 113}},
+		{"root vector", 18, []byte{0, 0, 0, 4}},
+		{"cid", 22, cid[:]},
+		{"framing flags and length", 38, []byte{0x70, 91}},
+		{"framing vector", 40, []byte{0, 0, 0, 2}},
+		{"source name", 44, []byte("blender\x00")},
+		{"priority, sync, sequence, options, universe", 108, []byte{150, 0, 0, 7, 0, 0x01, 0x02}},
+		{"dmp layer", 115, []byte{0x70, 14, 0x02, 0xa1, 0, 0, 0, 1, 0, 4, 0}},
+		{"data", 126, data},
+	}
+
+	for _, test := range tests {
+		if result := p[test.offset : test.offset+len(test.want)]; !bytes.Equal(result, test.want) {
+			t.Errorf("%v: Wanted % x, got: % x", test.name, test.want, result)
+		}
+	}
+}
+
+func TestE131MulticastAddr(t *testing.T) {
+	addr := E131MulticastAddr(0x0102)
+	if addr.String() != "239.255.1.2:5568" {
+		t.Errorf("Wanted %v, got: %v", "239.255.1.2:5568", addr)
+	}
+}
+
+func TestE131Sender(t *testing.T) {
+	// the listener stands in for the receiving controller
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Unable to listen on loopback: %v", err)
+	}
+	defer listener.Close()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+	s := NewE131Sender(conn, listener.LocalAddr(), NewMapping(1), [16]byte{}, "test")
+	s.SetPriority(120)
+	// 200 pixels need two universes
+	for f := 0; f < 2; f++ {
+		if err := s.WriteFrame(solidFrame(200, ic.RGBA{R: 9, A: 255})); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	want := []struct {
+		universe uint16
+		sequence uint8
+		length   int
+	}{
+		{1, 0, 510},
+		{2, 0, 90},
+		{1, 1, 510},
+		{2, 1, 90},
+	}
+	buf := make([]byte, 1024)
+	for i := range want {
+		listener.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := listener.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if universe := binary.BigEndian.Uint16(buf[113:]); universe != want[i].universe {
+			t.Errorf("Packet %v: Wanted universe %v, got: %v", i, want[i].universe, universe)
+		}
+		if buf[111] != want[i].sequence {
+			t.Errorf("Packet %v: Wanted sequence %v, got: %v", i, want[i].sequence, buf[111])
+		}
+		if buf[108] != 120 {
+			t.Errorf("Packet %v: Wanted priority %v, got: %v", i, 120, buf[108])
+		}
+		if n-e131HeaderLen != want[i].length || buf[e131HeaderLen] != 9 {
+			t.Errorf("Packet %v: Wanted %v channels starting with 9, got: %v starting with %v", i, want[i].length, n-e131HeaderLen, buf[e131HeaderLen])
+		}
+	}
+}
+
+// countingConn counts the packets written to it
+type countingConn struct {
+	net.PacketConn
+	packets int
+}
+
+func (c *countingConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	c.packets++
+	return len(p), nil
+}
+
+func TestE131SenderUniverseRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		universe  uint16
+		numPixels int
+		wantErr   bool
+	}{
+		{"first", 1, 1, false},
+		{"last", 63999, 170, false},
+		{"zero", 0, 1, true},
+		{"past the last", 64000, 1, true},
+		{"runs past the last", 63999, 171, true},
+	}
+
+	for _, test := range tests {
+		conn := &countingConn{}
+		s := NewE131Sender(conn, nil, NewMapping(test.universe), [16]byte{}, "test")
+		err := s.WriteFrame(solidFrame(test.numPixels, ic.RGBA{R: 9, A: 255}))
+		if (err != nil) != test.wantErr {
+			t.Errorf("%v: Wanted error %v, got: %v", test.name, test.wantErr, err)
+		}
+		// nothing is sent for a frame with a bad universe
+		if test.wantErr && conn.packets != 0 {
+			t.Errorf("%v: Wanted no packets, got: %v", test.name, conn.packets)
+		}
+	}
+}
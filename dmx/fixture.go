@@ -0,0 +1,205 @@
+package dmx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	imageColor "image/color"
+	"io"
+	"math"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// ChannelType is the function of one fixture channel
+type ChannelType string
+
+const (
+	// Red is the red intensity, or its high byte when followed by RedFine
+	Red ChannelType = "red"
+	// RedFine is the low byte of a 16 bit red intensity
+	RedFine ChannelType = "red_fine"
+	// Green is the green intensity, or its high byte when followed by GreenFine
+	Green ChannelType = "green"
+	// GreenFine is the low byte of a 16 bit green intensity
+	GreenFine ChannelType = "green_fine"
+	// Blue is the blue intensity, or its high byte when followed by BlueFine
+	Blue ChannelType = "blue"
+	// BlueFine is the low byte of a 16 bit blue intensity
+	BlueFine ChannelType = "blue_fine"
+	// White is the white intensity, fed from the largest part of the color that matches the profile's
+	// white point, the part shared equally by red, green and blue when the profile has none
+	White ChannelType = "white"
+	// WhiteFine is the low byte of a 16 bit white intensity
+	WhiteFine ChannelType = "white_fine"
+	// Amber is the amber intensity, fed from the part of the color shared by red and green
+	Amber ChannelType = "amber"
+	// UV is the ultraviolet intensity, which has no visible color so it is always zero
+	UV ChannelType = "uv"
+	// Dimmer is the master intensity, fed from the brightness (alpha) of the color
+	Dimmer ChannelType = "dimmer"
+	// DimmerFine is the low byte of a 16 bit master intensity
+	DimmerFine ChannelType = "dimmer_fine"
+	// Unused is a channel that is always zero
+	Unused ChannelType = "unused"
+)
+
+// amber is the color an amber emitter adds, as a fraction of full red and green
+const (
+	amberR = 1.0
+	amberG = 0.75
+)
+
+// Profile describes the channel layout of a fixture
+type Profile struct {
+	Name     string        `json:"name"`
+	Channels []ChannelType `json:"channels"`
+	// WhitePoint is the color of the white emitter, such as "2700K" for a warm white. Nil is equal
+	// red, green and blue.
+	WhitePoint *color.Color `json:"white_point,omitempty"`
+}
+
+// Common fixture profiles
+var (
+	ProfileRGB       = Profile{Name: "RGB", Channels: []ChannelType{Red, Green, Blue}}
+	ProfileRGBW      = Profile{Name: "RGBW", Channels: []ChannelType{Red, Green, Blue, White}}
+	ProfileRGBAUV    = Profile{Name: "RGBA-UV", Channels: []ChannelType{Red, Green, Blue, Amber, UV}}
+	ProfileDimmerRGB = Profile{Name: "Dimmer+RGB", Channels: []ChannelType{Dimmer, Red, Green, Blue}}
+	ProfileRGB16     = Profile{Name: "RGB16", Channels: []ChannelType{Red, RedFine, Green, GreenFine, Blue, BlueFine}}
+)
+
+// LoadProfiles reads a JSON array of profiles and validates each of them
+func LoadProfiles(r io.Reader) ([]Profile, error) {
+	var profiles []Profile
+	if err := json.NewDecoder(r).Decode(&profiles); err != nil {
+		return nil, err
+	}
+	for i := range profiles {
+		if err := profiles[i].Validate(); err != nil {
+			return nil, fmt.Errorf("profile %d (%s): %v", i, profiles[i].Name, err)
+		}
+	}
+	return profiles, nil
+}
+
+// Validate checks that the profile has channels, that each channel type is known and not repeated,
+// and that each fine channel follows its coarse channel
+func (p *Profile) Validate() error {
+	if len(p.Channels) == 0 {
+		return errors.New("profile has no channels")
+	}
+	seen := map[ChannelType]bool{}
+	for i, ch := range p.Channels {
+		coarse, isFine := fineChannels[ch]
+		switch {
+		case isFine:
+			if i == 0 || p.Channels[i-1] != coarse {
+				return fmt.Errorf("channel %d: %s must follow %s", i+1, ch, coarse)
+			}
+		case ch == Red, ch == Green, ch == Blue, ch == White, ch == Amber, ch == UV, ch == Dimmer, ch == Unused:
+		default:
+			return fmt.Errorf("channel %d: unknown channel type %q", i+1, ch)
+		}
+		if seen[ch] && ch != Unused {
+			return fmt.Errorf("channel %d: %s is repeated", i+1, ch)
+		}
+		seen[ch] = true
+	}
+	if p.WhitePoint != nil {
+		if wp := p.WhitePoint.GetColor(); wp.R == 0 && wp.G == 0 && wp.B == 0 {
+			return errors.New("white point must not be black")
+		}
+	}
+	return nil
+}
+
+// fineChannels maps each fine channel to the coarse channel it follows
+var fineChannels = map[ChannelType]ChannelType{
+	RedFine:    Red,
+	GreenFine:  Green,
+	BlueFine:   Blue,
+	WhiteFine:  White,
+	DimmerFine: Dimmer,
+}
+
+// ChannelCount returns the number of DMX channels the fixture uses
+func (p *Profile) ChannelCount() int {
+	return len(p.Channels)
+}
+
+// Encode writes the channel values for the color into buf and returns the number of bytes written.
+// With a dimmer channel the brightness (alpha) goes to the dimmer, otherwise it scales the color channels.
+func (p *Profile) Encode(buf []byte, c *color.Color) (int, error) {
+	if len(buf) < len(p.Channels) {
+		return 0, errors.New("buffer too small for fixture channels")
+	}
+	levels := p.levels(c.GetColor())
+	for i, ch := range p.Channels {
+		if coarse, ok := fineChannels[ch]; ok {
+			buf[i] = uint8(to16Bit(levels[coarse]))
+		} else {
+			buf[i] = uint8(to16Bit(levels[ch]) >> 8)
+		}
+	}
+	return len(p.Channels), nil
+}
+
+// levels splits the color into the intensity of each channel type in the range [0, 1]
+func (p *Profile) levels(rgba imageColor.RGBA) map[ChannelType]float64 {
+	has := map[ChannelType]bool{}
+	for _, ch := range p.Channels {
+		has[ch] = true
+	}
+	r := float64(rgba.R) / math.MaxUint8
+	g := float64(rgba.G) / math.MaxUint8
+	b := float64(rgba.B) / math.MaxUint8
+	brightness := float64(rgba.A) / math.MaxUint8
+	levels := map[ChannelType]float64{}
+	// the brightness goes to the dimmer if there is one
+	if has[Dimmer] {
+		levels[Dimmer] = brightness
+	} else {
+		r, g, b = r*brightness, g*brightness, b*brightness
+	}
+	// the largest part of the color in the white point's proportions is white
+	if has[White] {
+		wr, wg, wb := p.whitePoint()
+		w := 1.0
+		for _, part := range [][2]float64{{r, wr}, {g, wg}, {b, wb}} {
+			// a white point with none of a component puts no limit on the white from it
+			if part[1] > 0 {
+				w = math.Min(w, part[0]/part[1])
+			}
+		}
+		levels[White] = w
+		r, g, b = math.Max(0, r-w*wr), math.Max(0, g-w*wg), math.Max(0, b-w*wb)
+	}
+	// the part shared by red and green, in amber's proportions, is amber
+	if has[Amber] {
+		a := math.Min(r/amberR, g/amberG)
+		levels[Amber] = a
+		r, g = r-a*amberR, g-a*amberG
+	}
+	levels[Red], levels[Green], levels[Blue] = r, g, b
+	return levels
+}
+
+// whitePoint returns the color of the white emitter as fractions of full red, green and blue
+func (p *Profile) whitePoint() (r float64, g float64, b float64) {
+	if p.WhitePoint == nil {
+		return 1, 1, 1
+	}
+	wp := p.WhitePoint.GetColor()
+	return float64(wp.R) / math.MaxUint8, float64(wp.G) / math.MaxUint8, float64(wp.B) / math.MaxUint8
+}
+
+// to16Bit converts a level in the range [0, 1] to a 16 bit channel value
+func to16Bit(level float64) uint16 {
+	if level <= 0 {
+		return 0
+	}
+	if level >= 1 {
+		return math.MaxUint16
+	}
+	return uint16(math.Round(level * math.MaxUint16))
+}
@@ -0,0 +1,127 @@
+package dmx
+
+import (
+	"bytes"
+	ic "image/color"
+	"strings"
+	"testing"
+
+	"github.com/gazek/color-blender/color"
+)
+
+func TestProfileEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile Profile
+		color   ic.RGBA
+		want    []byte
+	}{
+		{"rgb", ProfileRGB, ic.RGBA{R: 255, G: 128, B: 0, A: 255}, []byte{255, 128, 0}},
+		{"rgb half brightness", ProfileRGB, ic.RGBA{R: 255, G: 128, B: 0, A: 128}, []byte{128, 64, 0}},
+		{"rgbw", ProfileRGBW, ic.RGBA{R: 255, G: 200, B: 100, A: 255}, []byte{155, 100, 0, 100}},
+		{"rgbw white", ProfileRGBW, ic.RGBA{R: 255, G: 255, B: 255, A: 255}, []byte{0, 0, 0, 255}},
+		{"rgba-uv", ProfileRGBAUV, ic.RGBA{R: 255, G: 191, B: 0, A: 255}, []byte{0, 0, 0, 255, 0}},
+		{"rgba-uv red", ProfileRGBAUV, ic.RGBA{R: 255, G: 0, B: 10, A: 255}, []byte{255, 0, 10, 0, 0}},
+		{"dimmer", ProfileDimmerRGB, ic.RGBA{R: 255, G: 128, B: 0, A: 128}, []byte{128, 255, 128, 0}},
+		{"rgb16", ProfileRGB16, ic.RGBA{R: 255, G: 128, B: 1, A: 128}, []byte{128, 128, 64, 129, 0, 129}},
+		{"unused", Profile{Channels: []ChannelType{Unused, Red, Unused}}, ic.RGBA{R: 10, A: 255}, []byte{0, 10, 0}},
+	}
+
+	for _, test := range tests {
+		if err := test.profile.Validate(); err != nil {
+			t.Errorf("%v: unexpected error: %v", test.name, err)
+		}
+		buf := make([]byte, test.profile.ChannelCount())
+		n, err := test.profile.Encode(buf, color.NewColor(test.color))
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !bytes.Equal(buf[:n], test.want) {
+			t.Errorf("%v: Wanted %v, got: %v", test.name, test.want, buf[:n])
+		}
+	}
+	if _, err := ProfileRGB.Encode(make([]byte, 2), color.NewColor(ic.RGBA{})); err == nil {
+		t.Error("Wanted an error for a short buffer")
+	}
+}
+
+func TestProfileEncodeWhitePoint(t *testing.T) {
+	warm := color.Kelvin(2700)
+	p := Profile{Name: "warm RGBW", Channels: ProfileRGBW.Channels, WhitePoint: color.NewColor(warm)}
+	tests := []struct {
+		name  string
+		color ic.RGBA
+		want  []byte
+	}{
+		{"warm white", warm, []byte{0, 0, 0, 255}},
+		{"dim warm white", ic.RGBA{R: warm.R, G: warm.G, B: warm.B, A: 128}, []byte{0, 0, 0, 128}},
+		// equal white is warm white plus the blue the warm emitter lacks
+		{"neutral white", ic.RGBA{R: 255, G: 255, B: 255, A: 255}, []byte{0, 255 - warm.G, 255 - warm.B, 255}},
+		{"red", ic.RGBA{R: 255, A: 255}, []byte{255, 0, 0, 0}},
+	}
+
+	for _, test := range tests {
+		buf := make([]byte, p.ChannelCount())
+		if _, err := p.Encode(buf, color.NewColor(test.color)); err != nil {
+			t.Fatalf("%v: unexpected error: %v", test.name, err)
+		}
+		for i := range test.want {
+			if d := int(buf[i]) - int(test.want[i]); d < -1 || d > 1 {
+				t.Errorf("%v: Wanted %v, got: %v", test.name, test.want, buf)
+				break
+			}
+		}
+	}
+}
+
+func TestProfileValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		channels []ChannelType
+	}{
+		{"empty", nil},
+		{"unknown", []ChannelType{Red, "magenta"}},
+		{"repeated", []ChannelType{Red, Red}},
+		{"fine first", []ChannelType{RedFine, Red}},
+		{"fine apart", []ChannelType{Red, Green, RedFine}},
+	}
+
+	for _, test := range tests {
+		p := Profile{Name: test.name, Channels: test.channels}
+		if err := p.Validate(); err == nil {
+			t.Errorf("%v: Wanted an error", test.name)
+		}
+	}
+	black := Profile{Name: "black white point", Channels: ProfileRGBW.Channels, WhitePoint: color.NewColor(ic.RGBA{A: 255})}
+	if err := black.Validate(); err == nil {
+		t.Errorf("%v: Wanted an error", black.Name)
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	input := `[
+		{"name": "par", "channels": ["dimmer", "red", "green", "blue", "white"]},
+		{"name": "bar16", "channels": ["red", "red_fine", "green", "green_fine", "blue", "blue_fine"]},
+		{"name": "warm", "channels": ["red", "green", "blue", "white"], "white_point": "2700K"}
+	]`
+	profiles, err := LoadProfiles(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(profiles) != 3 || profiles[0].Name != "par" || profiles[1].ChannelCount() != 6 || profiles[2].WhitePoint.GetColor() != color.Kelvin(2700) {
+		t.Errorf("Unexpected profiles: %v", profiles)
+	}
+	buf := make([]byte, 5)
+	profiles[0].Encode(buf, color.NewColor(ic.RGBA{R: 255, G: 255, B: 100, A: 255}))
+	want := []byte{255, 155, 155, 0, 100}
+	if !bytes.Equal(buf, want) {
+		t.Errorf("Wanted %v, got: %v", want, buf)
+	}
+	// invalid profiles should be rejected
+	for _, bad := range []string{`[{"name": "x", "channels": ["red", "pink"]}]`, `{`} {
+		if _, err := LoadProfiles(strings.NewReader(bad)); err == nil {
+			t.Errorf("Wanted an error for %v", bad)
+		}
+	}
+}
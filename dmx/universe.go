@@ -0,0 +1,91 @@
+package dmx
+
+import (
+	"errors"
+
+	"github.com/gazek/color-blender/color"
+	"github.com/gazek/color-blender/output"
+)
+
+// UniverseSize is the number of channels in a DMX universe
+const UniverseSize = 512
+
+// Universe holds the channel values for one DMX universe. Data[0] is channel 1.
+type Universe struct {
+	Number uint16
+	Data   []byte
+}
+
+// Mapping describes how the pixels of a frame are laid out over one or more universes.
+// Pixels never straddle two universes, a strip too long for one universe continues at
+// channel 1 of the next universe.
+type Mapping struct {
+	// Universe is the number of the first universe
+	Universe uint16
+	// StartAddress is the channel (1-512) of the first pixel in the first universe
+	StartAddress int
+	// Order is the order of the color channels of each pixel
+	Order output.ChannelOrder
+}
+
+// NewMapping creates a new Mapping that starts at channel 1 of the universe and uses RGB order
+func NewMapping(universe uint16) Mapping {
+	return Mapping{Universe: universe, StartAddress: 1, Order: output.RGB}
+}
+
+// Validate checks that the start address is a DMX channel
+func (m Mapping) Validate() error {
+	if m.StartAddress < 1 || m.StartAddress > UniverseSize-2 {
+		return errors.New("start address must leave room for a pixel between channels 1 and 510")
+	}
+	return nil
+}
+
+// Map splits the frame into universes, each color is scaled by its brightness (alpha).
+// The universes reuse dst when it has enough capacity.
+func (m Mapping) Map(dst []Universe, frame []color.Color) ([]Universe, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	dst = dst[:0]
+	number := m.Universe
+	channel := m.StartAddress - 1
+	var u *Universe
+	for i := range frame {
+		// start a new universe when the pixel doesn't fit
+		if u == nil || channel+3 > UniverseSize {
+			if u != nil {
+				number++
+				channel = 0
+			}
+			dst = m.nextUniverse(dst, number)
+			u = &dst[len(dst)-1]
+		}
+		r, g, b := output.Channels(&frame[i])
+		u.Data = u.Data[:channel+3]
+		m.Order.Put(u.Data[channel:], r, g, b)
+		channel += 3
+	}
+	return dst, nil
+}
+
+// nextUniverse appends an empty universe to dst, reusing its previous data buffer when possible
+func (m Mapping) nextUniverse(dst []Universe, number uint16) []Universe {
+	if len(dst) < cap(dst) {
+		dst = dst[:len(dst)+1]
+	} else {
+		dst = append(dst, Universe{})
+	}
+	u := &dst[len(dst)-1]
+	u.Number = number
+	if cap(u.Data) < UniverseSize {
+		u.Data = make([]byte, 0, UniverseSize)
+	}
+	// channels before the start address are zero
+	u.Data = u.Data[:UniverseSize]
+	for i := range u.Data {
+		u.Data[i] = 0
+	}
+	u.Data = u.Data[:0]
+	return dst
+}
@@ -0,0 +1,85 @@
+package dmx
+
+import (
+	"bytes"
+	ic "image/color"
+	"testing"
+
+	"github.com/gazek/color-blender/color"
+	"github.com/gazek/color-blender/output"
+)
+
+// solidFrame creates a frame of numPixels fully bright pixels of the same color
+func solidFrame(numPixels int, c ic.RGBA) []color.Color {
+	frame := make([]color.Color, numPixels)
+	for i := range frame {
+		frame[i] = *color.NewColor(c)
+	}
+	return frame
+}
+
+func TestMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		mapping   Mapping
+		numPixels int
+		wantNums  []uint16
+		wantLens  []int
+	}{
+		{"one universe", NewMapping(1), 2, []uint16{1}, []int{6}},
+		{"full universe", NewMapping(1), 170, []uint16{1}, []int{510}},
+		{"split", NewMapping(3), 200, []uint16{3, 4}, []int{510, 90}},
+		{"start address", Mapping{Universe: 0, StartAddress: 505, Order: output.RGB}, 3, []uint16{0, 1}, []int{510, 3}},
+		{"empty", NewMapping(1), 0, nil, nil},
+	}
+
+	for _, test := range tests {
+		universes, err := test.mapping.Map(nil, solidFrame(test.numPixels, ic.RGBA{R: 1, G: 2, B: 3, A: 255}))
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.name, err)
+			continue
+		}
+		if len(universes) != len(test.wantNums) {
+			t.Errorf("%v: Wanted %v universes, got: %v", test.name, len(test.wantNums), len(universes))
+			continue
+		}
+		for i := range universes {
+			if universes[i].Number != test.wantNums[i] {
+				t.Errorf("%v: Wanted universe %v, got: %v", test.name, test.wantNums[i], universes[i].Number)
+			}
+			if len(universes[i].Data) != test.wantLens[i] {
+				t.Errorf("%v: Wanted %v channels, got: %v", test.name, test.wantLens[i], len(universes[i].Data))
+			}
+		}
+	}
+}
+
+func TestMapChannels(t *testing.T) {
+	m := Mapping{Universe: 1, StartAddress: 3, Order: output.GRB}
+	frame := solidFrame(2, ic.RGBA{R: 1, G: 2, B: 3, A: 255})
+	universes, _ := m.Map(nil, frame)
+	want := []byte{0, 0, 2, 1, 3, 2, 1, 3}
+	if !bytes.Equal(universes[0].Data, want) {
+		t.Errorf("Wanted %v, got: %v", want, universes[0].Data)
+	}
+	// reusing the universes should clear the old channel values
+	m.StartAddress = 1
+	universes, _ = m.Map(universes, frame[:1])
+	want = []byte{2, 1, 3}
+	if !bytes.Equal(universes[0].Data, want) || len(universes) != 1 {
+		t.Errorf("Wanted %v, got: %v", want, universes[0].Data)
+	}
+	if universes[0].Data[:8][4] != 0 {
+		t.Error("Wanted the previous channel values cleared")
+	}
+}
+
+func TestMapErrors(t *testing.T) {
+	for _, start := range []int{0, 511} {
+		m := NewMapping(1)
+		m.StartAddress = start
+		if _, err := m.Map(nil, solidFrame(1, ic.RGBA{})); err == nil {
+			t.Errorf("Wanted an error for start address %v", start)
+		}
+	}
+}
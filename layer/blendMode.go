@@ -0,0 +1,69 @@
+package layer
+
+import "fmt"
+
+// BlendMode defines how a layer's color is combined with the colors beneath it
+type BlendMode int
+
+const (
+	// Normal places the layer color over the colors beneath it (alpha-over)
+	Normal BlendMode = iota
+	// Add sums the layer color with the colors beneath it
+	Add
+	// Multiply multiplies the layer color with the colors beneath it, which darkens
+	Multiply
+	// Screen inverts, multiplies and inverts again, which lightens
+	Screen
+	// Overlay multiplies dark areas and screens light areas of the colors beneath the layer
+	Overlay
+	// Lighten keeps the lighter of the layer color and the colors beneath it
+	Lighten
+	// Darken keeps the darker of the layer color and the colors beneath it
+	Darken
+	// Difference subtracts the darker of the two colors from the lighter one
+	Difference
+)
+
+func (m BlendMode) String() string {
+	if m < Normal || m > Difference {
+		return fmt.Sprintf("BlendMode(%d)", int(m))
+	}
+	return [...]string{"Normal", "Add", "Multiply", "Screen", "Overlay", "Lighten", "Darken", "Difference"}[m]
+}
+
+// blend combines a backdrop component value with a source component value, both in the range [0, 1]
+func (m BlendMode) blend(backdrop float32, source float32) float32 {
+	switch m {
+	case Add:
+		if backdrop+source > 1 {
+			return 1
+		}
+		return backdrop + source
+	case Multiply:
+		return backdrop * source
+	case Screen:
+		return backdrop + source - backdrop*source
+	case Overlay:
+		if backdrop <= 0.5 {
+			return 2 * backdrop * source
+		}
+		return 1 - 2*(1-backdrop)*(1-source)
+	case Lighten:
+		if source > backdrop {
+			return source
+		}
+		return backdrop
+	case Darken:
+		if source < backdrop {
+			return source
+		}
+		return backdrop
+	case Difference:
+		if source > backdrop {
+			return source - backdrop
+		}
+		return backdrop - source
+	default:
+		return source
+	}
+}
@@ -0,0 +1,58 @@
+package layer
+
+import (
+	"testing"
+)
+
+func TestBlend(t *testing.T) {
+	tests := []struct {
+		mode     BlendMode
+		backdrop float32
+		source   float32
+		want     float32
+	}{
+		{Normal, 0.25, 0.5, 0.5},
+		{Add, 0.25, 0.5, 0.75},
+		{Add, 0.75, 0.5, 1},
+		{Multiply, 0.5, 0.5, 0.25},
+		{Screen, 0.5, 0.5, 0.75},
+		{Overlay, 0.25, 0.5, 0.25},
+		{Overlay, 0.75, 0.5, 0.75},
+		{Lighten, 0.25, 0.5, 0.5},
+		{Lighten, 0.75, 0.5, 0.75},
+		{Darken, 0.25, 0.5, 0.25},
+		{Darken, 0.75, 0.5, 0.5},
+		{Difference, 0.25, 0.75, 0.5},
+		{Difference, 0.75, 0.25, 0.5},
+		{BlendMode(42), 0.25, 0.5, 0.5},
+	}
+
+	for _, test := range tests {
+		if result := test.mode.blend(test.backdrop, test.source); result != test.want {
+			t.Errorf("%v: Wanted %v, got: %v", test.mode, test.want, result)
+		}
+	}
+}
+
+func TestBlendModeString(t *testing.T) {
+	tests := []struct {
+		mode BlendMode
+		want string
+	}{
+		{Normal, "Normal"},
+		{Add, "Add"},
+		{Multiply, "Multiply"},
+		{Screen, "Screen"},
+		{Overlay, "Overlay"},
+		{Lighten, "Lighten"},
+		{Darken, "Darken"},
+		{Difference, "Difference"},
+		{BlendMode(42), "BlendMode(42)"},
+	}
+
+	for _, test := range tests {
+		if result := test.mode.String(); result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package layer
+
+import (
+	ic "image/color"
+	"math"
+
+	"github.com/gazek/color-blender/color"
+	"github.com/gazek/color-blender/transfunc"
+)
+
+// Source is anything that can produce a color for the current step, such as a Blender or another Stack
+type Source interface {
+	GetColor() *color.Color
+}
+
+// stepper is implemented by sources that track their own step position
+type stepper interface {
+	AdvanceStep(numSteps int)
+	ResetStep()
+}
+
+// stepSource is implemented by sources that can calculate the color for any step position
+type stepSource interface {
+	GetPeriod() int
+	GetColorAtStep(step int) *color.Color
+}
+
+// Layer combines a Source with the blend mode and opacity used to composite it
+type Layer struct {
+	Source       Source
+	Mode         BlendMode
+	Opacity      uint8
+	opacityFuncs transfunc.BrightnessFuncSlice
+}
+
+// NewLayer creates a new fully opaque Layer object
+func NewLayer(source Source, mode BlendMode) Layer {
+	return Layer{
+		Source:  source,
+		Mode:    mode,
+		Opacity: math.MaxUint8,
+	}
+}
+
+// AppendOpacityFunc appends the BrightnessFunc to the layer's opacity function slice
+func (l *Layer) AppendOpacityFunc(f transfunc.BrightnessFunc) {
+	l.opacityFuncs.AppendFunc(&f)
+}
+
+// getOpacity calculates the layer opacity for the given step position
+func (l *Layer) getOpacity(step int) float32 {
+	opacity := float32(l.Opacity) / math.MaxUint8
+	// scale by the opacity func value if we have one
+	if fv, ok := l.opacityFuncs.GetFuncValue(step); ok {
+		opacity *= float32(fv) / math.MaxUint8
+	}
+	return opacity
+}
+
+// Stack composites several layers, from the bottom up, into a single color
+type Stack struct {
+	layers []*Layer
+	step   int
+}
+
+// AppendLayer adds the Layer to the top of the stack
+func (s *Stack) AppendLayer(l Layer) {
+	s.layers = append(s.layers, &l)
+}
+
+// ResetStep sets the step position of the stack and of each of its sources to zero
+func (s *Stack) ResetStep() {
+	s.step = 0
+	for _, l := range s.layers {
+		if src, ok := l.Source.(stepper); ok {
+			src.ResetStep()
+		}
+	}
+}
+
+// AdvanceStep changes the step position of the stack and of each of its sources by the numSteps amount
+func (s *Stack) AdvanceStep(numSteps int) {
+	for _, l := range s.layers {
+		if src, ok := l.Source.(stepper); ok {
+			src.AdvanceStep(numSteps)
+		}
+	}
+	// get a common period for the opacity funcs
+	period := s.getPeriod()
+	// handle period of zero
+	if period == 0 {
+		s.step = 0
+		return
+	}
+	// set the step
+	s.step = (s.step + numSteps) % period
+	// don't let the step position go negative
+	if s.step < 0 {
+		s.step = 0
+	}
+}
+
+// GetPeriod returns the number of steps after which the opacity funcs and every source that has a period
+// all repeat together
+func (s *Stack) GetPeriod() int {
+	periods := []int{s.getPeriod()}
+	for _, l := range s.layers {
+		if src, ok := l.Source.(stepSource); ok {
+			periods = append(periods, src.GetPeriod())
+		}
+	}
+	return transfunc.CommonPeriod(periods...)
+}
+
+// GetColor composites the layers for the current step position
+func (s *Stack) GetColor() *color.Color {
+	return s.getColor(s.step, false)
+}
+
+// GetColorAtStep composites the layers for any step position. Sources that can calculate the color for
+// any step, such as a Blender, are used at that step, the others use the color for their current step.
+func (s *Stack) GetColorAtStep(step int) *color.Color {
+	return s.getColor(step, true)
+}
+
+// getColor composites the layers, when atStep is set the sources are used at the given step if they can be
+func (s *Stack) getColor(step int, atStep bool) *color.Color {
+	// start from a fully transparent backdrop
+	var r, g, b, a float32
+	for _, l := range s.layers {
+		// skip layers that can't contribute
+		if l.Source == nil {
+			continue
+		}
+		opacity := l.getOpacity(step)
+		if opacity == 0 {
+			continue
+		}
+		// the color's brightness is its coverage
+		var src ic.RGBA
+		if at, ok := l.Source.(stepSource); ok && atStep {
+			src = at.GetColorAtStep(step).GetColor()
+		} else {
+			src = l.Source.GetColor().GetColor()
+		}
+		srcA := float32(src.A) / math.MaxUint8 * opacity
+		if srcA == 0 {
+			continue
+		}
+		// composite the layer over the backdrop
+		outA := srcA + a*(1-srcA)
+		r = composite(l.Mode, r, a, float32(src.R)/math.MaxUint8, srcA, outA)
+		g = composite(l.Mode, g, a, float32(src.G)/math.MaxUint8, srcA, outA)
+		b = composite(l.Mode, b, a, float32(src.B)/math.MaxUint8, srcA, outA)
+		a = outA
+	}
+	// return the resulting color
+	return color.NewColor(ic.RGBA{
+		R: toUint8(r),
+		G: toUint8(g),
+		B: toUint8(b),
+		A: toUint8(a),
+	})
+}
+
+// getPeriod returns the least common multiple of the opacity func periods, the same as the Blender
+func (s *Stack) getPeriod() int {
+	periods := make([]int, len(s.layers))
+	for i, l := range s.layers {
+		periods[i] = l.opacityFuncs.GetPeriod()
+	}
+	return transfunc.CommonPeriod(periods...)
+}
+
+// composite combines one component of the source with the backdrop using the blend mode
+// and returns the resulting non-premultiplied component value
+func composite(mode BlendMode, backdrop float32, backdropA float32, source float32, sourceA float32, outA float32) float32 {
+	if outA == 0 {
+		return 0
+	}
+	// where the backdrop is covered the source is blended with it, elsewhere the source is used as is
+	mixed := (1-backdropA)*source + backdropA*mode.blend(backdrop, source)
+	// apply the source coverage over the backdrop
+	return (sourceA*mixed + (1-sourceA)*backdropA*backdrop) / outA
+}
+
+// toUint8 converts a component value in the range [0, 1] to a uint8
+func toUint8(value float32) uint8 {
+	if value <= 0 {
+		return 0
+	}
+	if value >= 1 {
+		return math.MaxUint8
+	}
+	return uint8(math.Round(float64(value * math.MaxUint8)))
+}
@@ -0,0 +1,182 @@
+package layer
+
+import (
+	ic "image/color"
+	"testing"
+
+	"github.com/gazek/color-blender/color"
+	"github.com/gazek/color-blender/transfunc"
+)
+
+type staticSource struct {
+	color ic.RGBA
+	step  int
+}
+
+func (s *staticSource) GetColor() *color.Color {
+	return color.NewColor(s.color)
+}
+
+func (s *staticSource) AdvanceStep(numSteps int) {
+	s.step += numSteps
+}
+
+func (s *staticSource) ResetStep() {
+	s.step = 0
+}
+
+// rampSource has a red component of 50 times the step, it repeats every 5 steps
+type rampSource struct {
+	step int
+}
+
+func (s *rampSource) GetColor() *color.Color {
+	return s.GetColorAtStep(s.step)
+}
+
+func (s *rampSource) GetPeriod() int {
+	return 5
+}
+
+func (s *rampSource) GetColorAtStep(step int) *color.Color {
+	return color.NewColor(ic.RGBA{R: uint8(50 * (step % 5)), A: 255})
+}
+
+func TestStackGetColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		colors  []ic.RGBA
+		modes   []BlendMode
+		opacity []uint8
+		want    ic.RGBA
+	}{
+		{"empty", nil, nil, nil, ic.RGBA{}},
+		{"single", []ic.RGBA{{R: 200, G: 100, A: 255}}, []BlendMode{Normal}, []uint8{255}, ic.RGBA{R: 200, G: 100, A: 255}},
+		{"normal over", []ic.RGBA{{R: 255, A: 255}, {B: 255, A: 255}}, []BlendMode{Normal, Normal}, []uint8{255, 255}, ic.RGBA{B: 255, A: 255}},
+		{"half coverage", []ic.RGBA{{R: 255, A: 255}, {B: 255, A: 255}}, []BlendMode{Normal, Normal}, []uint8{255, 127}, ic.RGBA{R: 128, B: 127, A: 255}},
+		{"transparent layer", []ic.RGBA{{R: 255, A: 255}, {B: 255, A: 0}}, []BlendMode{Normal, Add}, []uint8{255, 255}, ic.RGBA{R: 255, A: 255}},
+		{"add", []ic.RGBA{{R: 200, A: 255}, {R: 100, B: 255, A: 255}}, []BlendMode{Normal, Add}, []uint8{255, 255}, ic.RGBA{R: 255, B: 255, A: 255}},
+		{"multiply", []ic.RGBA{{R: 255, G: 128, A: 255}, {R: 128, G: 255, A: 255}}, []BlendMode{Normal, Multiply}, []uint8{255, 255}, ic.RGBA{R: 128, G: 128, A: 255}},
+		{"screen", []ic.RGBA{{R: 255, A: 255}, {G: 255, A: 255}}, []BlendMode{Normal, Screen}, []uint8{255, 255}, ic.RGBA{R: 255, G: 255, A: 255}},
+		{"lighten", []ic.RGBA{{R: 100, G: 200, A: 255}, {R: 200, G: 100, A: 255}}, []BlendMode{Normal, Lighten}, []uint8{255, 255}, ic.RGBA{R: 200, G: 200, A: 255}},
+		{"darken", []ic.RGBA{{R: 100, G: 200, A: 255}, {R: 200, G: 100, A: 255}}, []BlendMode{Normal, Darken}, []uint8{255, 255}, ic.RGBA{R: 100, G: 100, A: 255}},
+		{"difference", []ic.RGBA{{R: 100, G: 200, A: 255}, {R: 200, G: 100, A: 255}}, []BlendMode{Normal, Difference}, []uint8{255, 255}, ic.RGBA{R: 100, G: 100, A: 255}},
+		{"blend over nothing", []ic.RGBA{{R: 100, A: 255}}, []BlendMode{Multiply}, []uint8{255}, ic.RGBA{R: 100, A: 255}},
+	}
+
+	for _, test := range tests {
+		s := Stack{}
+		for i := range test.colors {
+			l := NewLayer(&staticSource{color: test.colors[i]}, test.modes[i])
+			l.Opacity = test.opacity[i]
+			s.AppendLayer(l)
+		}
+		if result := s.GetColor().GetColor(); result != test.want {
+			t.Errorf("%v: Wanted %v, got: %v", test.name, test.want, result)
+		}
+	}
+}
+
+func TestStackOpacityFunc(t *testing.T) {
+	s := Stack{}
+	s.AppendLayer(NewLayer(&staticSource{color: ic.RGBA{R: 255, A: 255}}, Normal))
+	l := NewLayer(&staticSource{color: ic.RGBA{B: 255, A: 255}}, Normal)
+	l.AppendOpacityFunc(transfunc.NewBrightnessFunc(func(x float32) float32 { return x }, 2, []float32{0, 2}))
+	s.AppendLayer(l)
+	want := []ic.RGBA{
+		{R: 255, A: 255},
+		{B: 255, A: 255},
+		{R: 255, A: 255},
+	}
+	for i := range want {
+		if result := s.GetColor().GetColor(); result != want[i] {
+			t.Errorf("Step %v: Wanted %v, got: %v", i, want[i], result)
+		}
+		s.AdvanceStep(1)
+	}
+}
+
+func TestStackAdvanceStepAndResetStep(t *testing.T) {
+	src := &staticSource{}
+	s := Stack{}
+	s.AppendLayer(NewLayer(src, Normal))
+	s.AdvanceStep(3)
+	if src.step != 3 {
+		t.Errorf("Wanted: %v, found: %v", 3, src.step)
+	}
+	// no opacity funcs means the stack itself has no period
+	if s.step != 0 {
+		t.Errorf("Wanted: %v, found: %v", 0, s.step)
+	}
+	s.ResetStep()
+	if src.step != 0 {
+		t.Errorf("Wanted: %v, found: %v", 0, src.step)
+	}
+}
+
+func TestStackGetPeriod(t *testing.T) {
+	tests := []struct {
+		periods []int
+		want    int
+	}{
+		{nil, 0},
+		{[]int{0, 0}, 0},
+		{[]int{4, 6}, 12},
+		{[]int{5, 0, 5}, 5},
+	}
+
+	for _, test := range tests {
+		s := Stack{}
+		for _, p := range test.periods {
+			l := NewLayer(&staticSource{}, Normal)
+			l.AppendOpacityFunc(transfunc.NewBrightnessFunc(func(x float32) float32 { return 1 }, p, nil))
+			s.AppendLayer(l)
+		}
+		if result := s.getPeriod(); result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
+
+func TestStackGetColorAtStep(t *testing.T) {
+	s := Stack{}
+	s.AppendLayer(NewLayer(&rampSource{step: 1}, Normal))
+	s.AppendLayer(NewLayer(&staticSource{color: ic.RGBA{B: 255, A: 255}}, Add))
+	l := NewLayer(&staticSource{color: ic.RGBA{G: 255, A: 255}}, Add)
+	// the green layer is only visible on the first of every two steps
+	l.AppendOpacityFunc(transfunc.NewBrightnessFunc(func(x float32) float32 { return 1 - x }, 2, []float32{0, 2}))
+	s.AppendLayer(l)
+	if s.GetPeriod() != 10 {
+		t.Errorf("Wanted: %v, found: %v", 10, s.GetPeriod())
+	}
+	tests := []struct {
+		step int
+		want ic.RGBA
+	}{
+		{0, ic.RGBA{G: 255, B: 255, A: 255}},
+		{3, ic.RGBA{R: 150, B: 255, A: 255}},
+		{8, ic.RGBA{R: 150, G: 255, B: 255, A: 255}},
+	}
+
+	for _, test := range tests {
+		if result := s.GetColorAtStep(test.step).GetColor(); result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+	// the current color uses the source's own step
+	if result := s.GetColor().GetColor().R; result != 50 {
+		t.Errorf("Wanted: %v, found: %v", 50, result)
+	}
+}
+
+func TestNestedStack(t *testing.T) {
+	inner := &Stack{}
+	inner.AppendLayer(NewLayer(&staticSource{color: ic.RGBA{G: 255, A: 255}}, Normal))
+	outer := Stack{}
+	outer.AppendLayer(NewLayer(&staticSource{color: ic.RGBA{R: 255, A: 255}}, Normal))
+	outer.AppendLayer(NewLayer(inner, Add))
+	want := ic.RGBA{R: 255, G: 255, A: 255}
+	if result := outer.GetColor().GetColor(); result != want {
+		t.Errorf("Wanted %v, got: %v", want, result)
+	}
+}
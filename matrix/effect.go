@@ -0,0 +1,74 @@
+package matrix
+
+import (
+	"math"
+
+	"github.com/gazek/color-blender/spatial"
+)
+
+// Effect returns the step offset of the pixel at the given position on the layout
+type Effect func(p Point, l *Layout) int
+
+// PhaseMap turns an Effect into a spatial.PhaseMap so a spatial.Renderer can drive the matrix.
+// Pixels that are not in the layout have an offset of zero.
+func (l *Layout) PhaseMap(effect Effect) spatial.PhaseMap {
+	return func(pixel int, numPixels int) int {
+		if pixel < 0 || pixel >= len(l.coords) {
+			return 0
+		}
+		return effect(l.coords[pixel], l)
+	}
+}
+
+// Horizontal delays each column by stepsPerPixel steps, so the pattern travels from left to right
+func Horizontal(stepsPerPixel int) Effect {
+	return func(p Point, l *Layout) int {
+		return -p.X * stepsPerPixel
+	}
+}
+
+// Vertical delays each row by stepsPerPixel steps, so the pattern travels from top to bottom
+func Vertical(stepsPerPixel int) Effect {
+	return func(p Point, l *Layout) int {
+		return -p.Y * stepsPerPixel
+	}
+}
+
+// Radial delays each pixel by its distance from the center of the matrix, so the pattern travels outward
+func Radial(stepsPerPixel int) Effect {
+	return func(p Point, l *Layout) int {
+		dx, dy := l.fromCenter(p)
+		return -int(math.Round(math.Hypot(dx, dy) * float64(stepsPerPixel)))
+	}
+}
+
+// Angular delays each pixel by its angle around the center of the matrix, so the pattern spins clockwise
+func Angular(stepsPerTurn int) Effect {
+	return func(p Point, l *Layout) int {
+		dx, dy := l.fromCenter(p)
+		// the fraction of a full turn, starting from the right
+		turn := math.Atan2(dy, dx) / (2 * math.Pi)
+		if turn < 0 {
+			turn++
+		}
+		return -int(math.Round(turn * float64(stepsPerTurn)))
+	}
+}
+
+// Plasma offsets each pixel by a sum of sine waves over x, y and the distance from the center.
+// The size is the wavelength in pixels and the offsets range over +/- steps.
+func Plasma(size float64, steps int) Effect {
+	return func(p Point, l *Layout) int {
+		x, y := float64(p.X), float64(p.Y)
+		dx, dy := l.fromCenter(p)
+		k := 2 * math.Pi / size
+		// average the waves so the result stays within [-1, 1]
+		v := (math.Sin(x*k) + math.Sin(y*k) + math.Sin((x+y)*k/2) + math.Sin(math.Hypot(dx, dy)*k)) / 4
+		return int(math.Round(v * float64(steps)))
+	}
+}
+
+// fromCenter returns the distance from the center of the matrix to the point along each axis
+func (l *Layout) fromCenter(p Point) (dx float64, dy float64) {
+	return float64(p.X) - float64(l.width-1)/2, float64(p.Y) - float64(l.height-1)/2
+}
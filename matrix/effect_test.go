@@ -0,0 +1,79 @@
+package matrix
+
+import (
+	ic "image/color"
+	"testing"
+
+	"github.com/gazek/color-blender/blender"
+	"github.com/gazek/color-blender/spatial"
+	"github.com/gazek/color-blender/transfunc"
+)
+
+func TestEffects(t *testing.T) {
+	l := Progressive(3, 3, Rows)
+	tests := []struct {
+		name   string
+		effect Effect
+		want   []int
+	}{
+		{"horizontal", Horizontal(2), []int{0, -2, -4, 0, -2, -4, 0, -2, -4}},
+		{"vertical", Vertical(2), []int{0, 0, 0, -2, -2, -2, -4, -4, -4}},
+		{"radial", Radial(10), []int{-14, -10, -14, -10, 0, -10, -14, -10, -14}},
+		{"angular", Angular(8), []int{-5, -6, -7, -4, 0, 0, -3, -2, -1}},
+	}
+
+	for _, test := range tests {
+		phaseMap := l.PhaseMap(test.effect)
+		for i := range test.want {
+			if result := phaseMap(i, l.NumPixels()); result != test.want[i] {
+				t.Errorf("%v pixel %v: Wanted %v, got: %v", test.name, i, test.want[i], result)
+			}
+		}
+	}
+}
+
+func TestPlasma(t *testing.T) {
+	l := Progressive(16, 16, Rows)
+	effect := Plasma(8, 20)
+	distinct := map[int]bool{}
+	for i := 0; i < l.NumPixels(); i++ {
+		result := effect(l.Coord(i), l)
+		if result < -20 || result > 20 {
+			t.Errorf("Pixel %v: Wanted an offset within +/-20, got: %v", i, result)
+		}
+		distinct[result] = true
+	}
+	if len(distinct) < 10 {
+		t.Errorf("Wanted a varied plasma, got %v distinct offsets", len(distinct))
+	}
+}
+
+func TestMatrixRenderer(t *testing.T) {
+	b := &blender.Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{R: 250}, transfunc.AllAtOnce, func(x float32) float32 { return x }, 5, []float32{0, 1}))
+	l := Serpentine(2, 2, Rows)
+	r := spatial.NewRenderer(b, l.NumPixels(), l.PhaseMap(Vertical(1)))
+	frame := r.Frame()
+	// the second row is wired right to left but both of its pixels are one step behind the first row
+	want := []uint8{0, 0, 200, 200}
+	for i := range want {
+		if result := frame[i].GetColor().R; result != want[i] {
+			t.Errorf("Pixel %v: Wanted %v, got: %v", i, want[i], result)
+		}
+	}
+}
+
+func TestMatrixRendererExtraPixels(t *testing.T) {
+	b := &blender.Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{R: 250}, transfunc.AllAtOnce, func(x float32) float32 { return x }, 5, []float32{0, 1}))
+	l := Serpentine(2, 2, Rows)
+	// pixels past the end of the layout get the default offset
+	r := spatial.NewRenderer(b, l.NumPixels()+2, l.PhaseMap(Vertical(1)))
+	frame := r.Frame()
+	want := []uint8{0, 0, 200, 200, 0, 0}
+	for i := range want {
+		if result := frame[i].GetColor().R; result != want[i] {
+			t.Errorf("Pixel %v: Wanted %v, got: %v", i, want[i], result)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// Point is the position of a pixel on the matrix, x increases to the right and y increases downward
+type Point struct {
+	X int
+	Y int
+}
+
+// Order defines which direction the pixels are wired in first
+type Order int
+
+const (
+	// Rows wires the pixels one row at a time
+	Rows Order = iota
+	// Columns wires the pixels one column at a time
+	Columns
+)
+
+func (o Order) String() string {
+	if o < Rows || o > Columns {
+		return fmt.Sprintf("Order(%d)", int(o))
+	}
+	return [...]string{"Rows", "Columns"}[o]
+}
+
+// Layout maps the wiring index of each pixel to its position on the matrix
+type Layout struct {
+	width  int
+	height int
+	coords []Point
+}
+
+// NewLayout creates a new Layout from the position of each pixel in wiring order
+func NewLayout(coords []Point) (*Layout, error) {
+	l := &Layout{coords: coords}
+	// size the matrix to fit the coordinates
+	seen := map[Point]bool{}
+	for i, p := range coords {
+		if p.X < 0 || p.Y < 0 {
+			return nil, fmt.Errorf("pixel %d has negative coordinates: %v", i, p)
+		}
+		if seen[p] {
+			return nil, fmt.Errorf("pixel %d has duplicate coordinates: %v", i, p)
+		}
+		seen[p] = true
+		if p.X >= l.width {
+			l.width = p.X + 1
+		}
+		if p.Y >= l.height {
+			l.height = p.Y + 1
+		}
+	}
+	return l, nil
+}
+
+// Progressive creates a Layout where every row (or column) is wired in the same direction
+func Progressive(width int, height int, order Order) *Layout {
+	return newGridLayout(width, height, order, false)
+}
+
+// Serpentine creates a Layout where every other row (or column) is wired in the opposite direction
+func Serpentine(width int, height int, order Order) *Layout {
+	return newGridLayout(width, height, order, true)
+}
+
+// newGridLayout creates a Layout for a fully populated width x height matrix
+func newGridLayout(width int, height int, order Order, zigzag bool) *Layout {
+	l := &Layout{width: width, height: height}
+	// the wiring runs along the major axis and steps along the minor axis
+	major, minor := width, height
+	if order == Columns {
+		major, minor = height, width
+	}
+	for j := 0; j < minor; j++ {
+		for i := 0; i < major; i++ {
+			pos := i
+			// reverse the odd lines of a serpentine layout
+			if zigzag && j%2 == 1 {
+				pos = major - 1 - i
+			}
+			if order == Columns {
+				l.coords = append(l.coords, Point{X: j, Y: pos})
+			} else {
+				l.coords = append(l.coords, Point{X: pos, Y: j})
+			}
+		}
+	}
+	return l
+}
+
+// Width returns the number of columns in the matrix
+func (l *Layout) Width() int {
+	return l.width
+}
+
+// Height returns the number of rows in the matrix
+func (l *Layout) Height() int {
+	return l.height
+}
+
+// NumPixels returns the number of wired pixels
+func (l *Layout) NumPixels() int {
+	return len(l.coords)
+}
+
+// Coord returns the position of the pixel with the given wiring index
+func (l *Layout) Coord(pixel int) Point {
+	return l.coords[pixel]
+}
+
+// Index returns the wiring index of the pixel at the given position
+func (l *Layout) Index(p Point) (int, bool) {
+	for i := range l.coords {
+		if l.coords[i] == p {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Rotate returns a new Layout turned clockwise by the given number of quarter turns
+func (l *Layout) Rotate(quarterTurns int) *Layout {
+	// only the remainder matters, and counter-clockwise turns are the same as three clockwise ones
+	quarterTurns = ((quarterTurns % 4) + 4) % 4
+	result := l.transform(func(p Point) Point { return p })
+	for t := 0; t < quarterTurns; t++ {
+		h := result.height
+		result = result.transform(func(p Point) Point { return Point{X: h - 1 - p.Y, Y: p.X} })
+		result.width, result.height = result.height, result.width
+	}
+	return result
+}
+
+// FlipX returns a new Layout mirrored left to right
+func (l *Layout) FlipX() *Layout {
+	return l.transform(func(p Point) Point { return Point{X: l.width - 1 - p.X, Y: p.Y} })
+}
+
+// FlipY returns a new Layout mirrored top to bottom
+func (l *Layout) FlipY() *Layout {
+	return l.transform(func(p Point) Point { return Point{X: p.X, Y: l.height - 1 - p.Y} })
+}
+
+// transform returns a new Layout of the same size with every coordinate passed through the function
+func (l *Layout) transform(f func(p Point) Point) *Layout {
+	result := &Layout{
+		width:  l.width,
+		height: l.height,
+		coords: make([]Point, len(l.coords)),
+	}
+	for i := range l.coords {
+		result.coords[i] = f(l.coords[i])
+	}
+	return result
+}
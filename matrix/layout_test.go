@@ -0,0 +1,95 @@
+package matrix
+
+import (
+	"testing"
+)
+
+func TestGridLayouts(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout *Layout
+		want   []Point
+	}{
+		{"progressive rows", Progressive(3, 2, Rows), []Point{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {1, 1}, {2, 1}}},
+		{"progressive columns", Progressive(3, 2, Columns), []Point{{0, 0}, {0, 1}, {1, 0}, {1, 1}, {2, 0}, {2, 1}}},
+		{"serpentine rows", Serpentine(3, 2, Rows), []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {1, 1}, {0, 1}}},
+		{"serpentine columns", Serpentine(3, 2, Columns), []Point{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {2, 0}, {2, 1}}},
+	}
+
+	for _, test := range tests {
+		if test.layout.NumPixels() != len(test.want) {
+			t.Errorf("%v: Wanted %v pixels, got: %v", test.name, len(test.want), test.layout.NumPixels())
+			continue
+		}
+		for i := range test.want {
+			if result := test.layout.Coord(i); result != test.want[i] {
+				t.Errorf("%v pixel %v: Wanted %v, got: %v", test.name, i, test.want[i], result)
+			}
+		}
+		if test.layout.Width() != 3 || test.layout.Height() != 2 {
+			t.Errorf("%v: Wanted 3x2, got: %vx%v", test.name, test.layout.Width(), test.layout.Height())
+		}
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	l := Progressive(3, 2, Rows)
+	tests := []struct {
+		name   string
+		layout *Layout
+		width  int
+		height int
+		want   []Point
+	}{
+		{"rotate 0", l.Rotate(0), 3, 2, []Point{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {1, 1}, {2, 1}}},
+		{"rotate 1", l.Rotate(1), 2, 3, []Point{{1, 0}, {1, 1}, {1, 2}, {0, 0}, {0, 1}, {0, 2}}},
+		{"rotate 2", l.Rotate(2), 3, 2, []Point{{2, 1}, {1, 1}, {0, 1}, {2, 0}, {1, 0}, {0, 0}}},
+		{"rotate -1", l.Rotate(-1), 2, 3, []Point{{0, 2}, {0, 1}, {0, 0}, {1, 2}, {1, 1}, {1, 0}}},
+		{"flip x", l.FlipX(), 3, 2, []Point{{2, 0}, {1, 0}, {0, 0}, {2, 1}, {1, 1}, {0, 1}}},
+		{"flip y", l.FlipY(), 3, 2, []Point{{0, 1}, {1, 1}, {2, 1}, {0, 0}, {1, 0}, {2, 0}}},
+	}
+
+	for _, test := range tests {
+		if test.layout.Width() != test.width || test.layout.Height() != test.height {
+			t.Errorf("%v: Wanted %vx%v, got: %vx%v", test.name, test.width, test.height, test.layout.Width(), test.layout.Height())
+		}
+		for i := range test.want {
+			if result := test.layout.Coord(i); result != test.want[i] {
+				t.Errorf("%v pixel %v: Wanted %v, got: %v", test.name, i, test.want[i], result)
+			}
+		}
+	}
+	// the original layout should not change
+	if result := l.Coord(1); result != (Point{1, 0}) {
+		t.Errorf("Wanted %v, got: %v", Point{1, 0}, result)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	l := Serpentine(3, 2, Rows)
+	if index, ok := l.Index(Point{0, 1}); !ok || index != 5 {
+		t.Errorf("Wanted %v, got: %v", 5, index)
+	}
+	if _, ok := l.Index(Point{3, 3}); ok {
+		t.Error("Wanted no pixel outside the matrix")
+	}
+}
+
+func TestNewLayoutErrors(t *testing.T) {
+	tests := [][]Point{
+		{{0, 0}, {-1, 0}},
+		{{0, 0}, {0, 0}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewLayout(test); err == nil {
+			t.Errorf("Wanted an error for %v", test)
+		}
+	}
+}
+
+func TestOrderString(t *testing.T) {
+	if Rows.String() != "Rows" || Columns.String() != "Columns" || Order(5).String() != "Order(5)" {
+		t.Errorf("Unexpected order names: %v, %v, %v", Rows, Columns, Order(5))
+	}
+}
@@ -0,0 +1,69 @@
+package matrix
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LoadCSV creates a Layout from a CSV grid where each cell holds the wiring index of the pixel at that position.
+// Empty cells, or cells holding a negative index, have no pixel.
+func LoadCSV(r io.Reader) (*Layout, error) {
+	reader := csv.NewReader(r)
+	// rows don't all need the same number of cells
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	// collect the coordinates by index
+	byIndex := map[int]Point{}
+	for y := range records {
+		for x := range records[y] {
+			cell := strings.TrimSpace(records[y][x])
+			if cell == "" {
+				continue
+			}
+			index, err := strconv.Atoi(cell)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pixel index %q at row %d, column %d", cell, y, x)
+			}
+			if index < 0 {
+				continue
+			}
+			if _, ok := byIndex[index]; ok {
+				return nil, fmt.Errorf("duplicate pixel index %d at row %d, column %d", index, y, x)
+			}
+			byIndex[index] = Point{X: x, Y: y}
+		}
+	}
+	// the indices must run from zero without gaps
+	coords := make([]Point, len(byIndex))
+	for i := range coords {
+		p, ok := byIndex[i]
+		if !ok {
+			return nil, fmt.Errorf("missing pixel index %d", i)
+		}
+		coords[i] = p
+	}
+	return NewLayout(coords)
+}
+
+// LoadJSON creates a Layout from a JSON array holding the [x, y] position of each pixel in wiring order
+func LoadJSON(r io.Reader) (*Layout, error) {
+	var pairs [][]int
+	if err := json.NewDecoder(r).Decode(&pairs); err != nil {
+		return nil, err
+	}
+	coords := make([]Point, len(pairs))
+	for i := range pairs {
+		if len(pairs[i]) != 2 {
+			return nil, fmt.Errorf("pixel %d must have exactly two coordinates, found %d", i, len(pairs[i]))
+		}
+		coords[i] = Point{X: pairs[i][0], Y: pairs[i][1]}
+	}
+	return NewLayout(coords)
+}
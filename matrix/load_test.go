@@ -0,0 +1,69 @@
+package matrix
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadCSV(t *testing.T) {
+	input := "0,1,2\n5,,3\n-1,4\n"
+	want := []Point{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {1, 2}, {0, 1}}
+	l, err := LoadCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if l.NumPixels() != len(want) {
+		t.Fatalf("Wanted %v pixels, got: %v", len(want), l.NumPixels())
+	}
+	for i := range want {
+		if result := l.Coord(i); result != want[i] {
+			t.Errorf("Pixel %v: Wanted %v, got: %v", i, want[i], result)
+		}
+	}
+	if l.Width() != 3 || l.Height() != 3 {
+		t.Errorf("Wanted 3x3, got: %vx%v", l.Width(), l.Height())
+	}
+}
+
+func TestLoadCSVErrors(t *testing.T) {
+	tests := []string{
+		"0,x\n",
+		"0,0\n",
+		"0,2\n",
+		"0,\"1\n",
+	}
+
+	for _, test := range tests {
+		if _, err := LoadCSV(strings.NewReader(test)); err == nil {
+			t.Errorf("Wanted an error for %q", test)
+		}
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	input := "[[0,0],[1,0],[1,1],[0,1]]"
+	want := []Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	l, err := LoadJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := range want {
+		if result := l.Coord(i); result != want[i] {
+			t.Errorf("Pixel %v: Wanted %v, got: %v", i, want[i], result)
+		}
+	}
+}
+
+func TestLoadJSONErrors(t *testing.T) {
+	tests := []string{
+		"[[0,0],[1]]",
+		"[[0,0],[0,0]]",
+		"{",
+	}
+
+	for _, test := range tests {
+		if _, err := LoadJSON(strings.NewReader(test)); err == nil {
+			t.Errorf("Wanted an error for %q", test)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package opc
+
+import (
+	"errors"
+	"net"
+
+	"github.com/gazek/color-blender/color"
+	"github.com/gazek/color-blender/output"
+)
+
+// Client streams frames to an Open Pixel Control server, it can be used as a player sink
+type Client struct {
+	conn    net.Conn
+	channel uint8
+	buf     []byte
+}
+
+// Dial connects to the OPC server at the address, such as "localhost:7890"
+func Dial(address string, channel uint8) (*Client, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn, channel), nil
+}
+
+// NewClient creates a new Client that sends to the channel over an existing connection.
+// Channel zero is a broadcast to every channel of the server.
+func NewClient(conn net.Conn, channel uint8) *Client {
+	return &Client{conn: conn, channel: channel}
+}
+
+// WriteFrame sends the frame as a set pixel colors message, each color is scaled by its brightness (alpha)
+func (c *Client) WriteFrame(frame []color.Color) error {
+	if 3*len(frame) > maxDataLen {
+		return errors.New("frame has too many pixels for one message")
+	}
+	if cap(c.buf) < 3*len(frame) {
+		c.buf = make([]byte, 3*len(frame))
+	}
+	c.buf = c.buf[:3*len(frame)]
+	for i := range frame {
+		r, g, b := output.Channels(&frame[i])
+		output.RGB.Put(c.buf[3*i:], r, g, b)
+	}
+	return WriteMessage(c.conn, Message{Channel: c.channel, Command: SetPixelColors, Data: c.buf})
+}
+
+// Close closes the connection to the server
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,41 @@
+package opc
+
+import (
+	"bytes"
+	ic "image/color"
+	"net"
+	"testing"
+
+	"github.com/gazek/color-blender/color"
+)
+
+func TestClientWriteFrame(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	c := NewClient(client, 3)
+	defer c.Close()
+	frame := []color.Color{
+		*color.NewColor(ic.RGBA{R: 10, G: 20, B: 30, A: 255}),
+		*color.NewColor(ic.RGBA{R: 255, G: 128, B: 0, A: 128}),
+	}
+	go c.WriteFrame(frame)
+	m, err := ReadMessage(server)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []byte{10, 20, 30, 128, 64, 0}
+	if m.Channel != 3 || m.Command != SetPixelColors || !bytes.Equal(m.Data, want) {
+		t.Errorf("Wanted %v, got: %v", want, m)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	if _, err := Dial("127.0.0.1:0", 0); err == nil {
+		t.Error("Wanted an error dialing port zero")
+	}
+	_, client := net.Pipe()
+	c := NewClient(client, 0)
+	if err := c.WriteFrame(make([]color.Color, maxDataLen/3+1)); err == nil {
+		t.Error("Wanted an error for too many pixels")
+	}
+}
@@ -0,0 +1,59 @@
+package opc
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Commands defined by the Open Pixel Control protocol
+const (
+	// SetPixelColors carries 8 bit RGB values for each pixel
+	SetPixelColors uint8 = 0
+	// SystemExclusive carries vendor specific data
+	SystemExclusive uint8 = 255
+)
+
+// headerLen is the length of the channel, command and length header of every message
+const headerLen = 4
+
+// maxDataLen is the largest message body the 16 bit length field can describe
+const maxDataLen = 0xffff
+
+// Message is a single Open Pixel Control message
+type Message struct {
+	Channel uint8
+	Command uint8
+	Data    []byte
+}
+
+// WriteMessage writes the header and data of the message to w in a single write
+func WriteMessage(w io.Writer, m Message) error {
+	if len(m.Data) > maxDataLen {
+		return errors.New("message data too long")
+	}
+	buf := make([]byte, headerLen+len(m.Data))
+	buf[0] = m.Channel
+	buf[1] = m.Command
+	binary.BigEndian.PutUint16(buf[2:], uint16(len(m.Data)))
+	copy(buf[headerLen:], m.Data)
+	_, err := w.Write(buf)
+	return err
+}
+
+// ReadMessage reads the next message from r
+func ReadMessage(r io.Reader) (Message, error) {
+	var header [headerLen]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return Message{}, err
+	}
+	m := Message{
+		Channel: header[0],
+		Command: header[1],
+		Data:    make([]byte, binary.BigEndian.Uint16(header[2:])),
+	}
+	if _, err := io.ReadFull(r, m.Data); err != nil {
+		return Message{}, err
+	}
+	return m, nil
+}
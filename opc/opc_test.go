@@ -0,0 +1,39 @@
+package opc
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestWriteReadMessage(t *testing.T) {
+	var buf bytes.Buffer
+	m := Message{Channel: 2, Command: SetPixelColors, Data: []byte{1, 2, 3, 4, 5, 6}}
+	if err := WriteMessage(&buf, m); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []byte{2, 0, 0, 6, 1, 2, 3, 4, 5, 6}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Wanted %v, got: %v", want, buf.Bytes())
+	}
+	result, err := ReadMessage(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Channel != m.Channel || result.Command != m.Command || !bytes.Equal(result.Data, m.Data) {
+		t.Errorf("Wanted %v, got: %v", m, result)
+	}
+	if _, err := ReadMessage(&buf); err != io.EOF {
+		t.Errorf("Wanted %v, got: %v", io.EOF, err)
+	}
+}
+
+func TestMessageErrors(t *testing.T) {
+	if err := WriteMessage(io.Discard, Message{Data: make([]byte, maxDataLen+1)}); err == nil {
+		t.Error("Wanted an error for too much data")
+	}
+	// the header promises more data than there is
+	if _, err := ReadMessage(bytes.NewReader([]byte{0, 0, 0, 6, 1, 2})); err == nil {
+		t.Error("Wanted an error for a truncated message")
+	}
+}
@@ -0,0 +1,146 @@
+package opc
+
+import (
+	ic "image/color"
+	"math"
+	"net"
+	"sync"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// Server receives Open Pixel Control messages and keeps the latest frame of each channel.
+// A frame sent on channel zero is a broadcast that replaces the frame of every channel.
+type Server struct {
+	listener net.Listener
+	mu       sync.Mutex
+	frames   map[uint8][]color.Color
+	// updates is signalled, without blocking, every time a frame is received
+	updates chan uint8
+	conns   map[net.Conn]bool
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// Listen creates a new Server listening on the address, such as "127.0.0.1:7890"
+func Listen(address string) (*Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	return NewServer(listener), nil
+}
+
+// NewServer creates a new Server that accepts connections from the listener
+func NewServer(listener net.Listener) *Server {
+	s := &Server{
+		listener: listener,
+		frames:   map[uint8][]color.Color{},
+		updates:  make(chan uint8, 16),
+		conns:    map[net.Conn]bool{},
+	}
+	s.wg.Add(1)
+	go s.accept()
+	return s
+}
+
+// Addr returns the address the server is listening on
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Updates returns a channel that receives the channel number of each frame as it arrives,
+// zero means every channel has changed. Updates are dropped when nobody is reading.
+func (s *Server) Updates() <-chan uint8 {
+	return s.updates
+}
+
+// Frame returns a copy of the latest frame received on the channel or broadcast on channel zero
+func (s *Server) Frame(channel uint8) []color.Color {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	latest, ok := s.frames[channel]
+	// channels without a frame of their own since the last broadcast show the broadcast
+	if !ok {
+		latest = s.frames[0]
+	}
+	frame := make([]color.Color, len(latest))
+	copy(frame, latest)
+	return frame
+}
+
+// Close stops accepting connections, closes the open ones and waits for them to finish
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// accept serves each incoming connection until the listener is closed
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = true
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.serve(conn)
+	}
+}
+
+// serve reads messages from the connection until it is closed
+func (s *Server) serve(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+	for {
+		// a disconnect or a malformed message ends the connection
+		m, err := ReadMessage(conn)
+		if err != nil {
+			return
+		}
+		if m.Command == SetPixelColors {
+			s.setFrame(m.Channel, m.Data)
+		}
+	}
+}
+
+// setFrame stores the pixel data as the latest frame of the channel, or of every channel for channel zero
+func (s *Server) setFrame(channel uint8, data []byte) {
+	frame := make([]color.Color, len(data)/3)
+	for i := range frame {
+		frame[i] = *color.NewColor(ic.RGBA{R: data[3*i], G: data[3*i+1], B: data[3*i+2], A: math.MaxUint8})
+	}
+	s.mu.Lock()
+	if channel == 0 {
+		// the broadcast is newer than any frame sent to a single channel
+		for c := range s.frames {
+			delete(s.frames, c)
+		}
+	}
+	s.frames[channel] = frame
+	s.mu.Unlock()
+	select {
+	case s.updates <- channel:
+	default:
+	}
+}
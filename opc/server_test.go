@@ -0,0 +1,131 @@
+package opc
+
+import (
+	ic "image/color"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gazek/color-blender/color"
+)
+
+func TestLoopback(t *testing.T) {
+	s, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Unable to listen on loopback: %v", err)
+	}
+	defer s.Close()
+	c, err := Dial(s.Addr().String(), 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer c.Close()
+	frames := [][]color.Color{
+		{*color.NewColor(ic.RGBA{R: 255, A: 255})},
+		{*color.NewColor(ic.RGBA{G: 255, A: 255}), *color.NewColor(ic.RGBA{B: 200, A: 255})},
+	}
+	for _, frame := range frames {
+		if err := c.WriteFrame(frame); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		select {
+		case channel := <-s.Updates():
+			if channel != 1 {
+				t.Errorf("Wanted channel %v, got: %v", 1, channel)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for the frame")
+		}
+		result := s.Frame(1)
+		if len(result) != len(frame) {
+			t.Fatalf("Wanted %v pixels, got: %v", len(frame), len(result))
+		}
+		for i := range frame {
+			if result[i].GetColor() != frame[i].GetColor() {
+				t.Errorf("Pixel %v: Wanted %v, got: %v", i, frame[i].GetColor(), result[i].GetColor())
+			}
+		}
+	}
+	// other channels have not received anything
+	if result := s.Frame(2); len(result) != 0 {
+		t.Errorf("Wanted an empty frame, got: %v", result)
+	}
+}
+
+func TestServerIgnoresOtherCommands(t *testing.T) {
+	s, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Unable to listen on loopback: %v", err)
+	}
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	WriteMessage(conn, Message{Channel: 0, Command: SystemExclusive, Data: []byte{1, 2, 3}})
+	WriteMessage(conn, Message{Channel: 0, Command: SetPixelColors, Data: []byte{4, 5, 6}})
+	select {
+	case <-s.Updates():
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the frame")
+	}
+	want := ic.RGBA{R: 4, G: 5, B: 6, A: 255}
+	if result := s.Frame(0); len(result) != 1 || result[0].GetColor() != want {
+		t.Errorf("Wanted %v, got: %v", want, result)
+	}
+	// closing the server closes the open connection
+	if err := s.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Error("Wanted the connection to be closed")
+	}
+}
+
+func TestServerBroadcast(t *testing.T) {
+	s, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Unable to listen on loopback: %v", err)
+	}
+	defer s.Close()
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer conn.Close()
+	red := ic.RGBA{R: 255, A: 255}
+	green := ic.RGBA{G: 255, A: 255}
+	blue := ic.RGBA{B: 255, A: 255}
+	tests := []struct {
+		channel uint8
+		data    []byte
+		// want holds the expected color of channels 0, 1 and 2 after the message
+		want []ic.RGBA
+	}{
+		{2, []byte{255, 0, 0}, []ic.RGBA{{}, {}, red}},
+		{0, []byte{0, 255, 0}, []ic.RGBA{green, green, green}},
+		{1, []byte{0, 0, 255}, []ic.RGBA{green, blue, green}},
+		{0, []byte{255, 0, 0}, []ic.RGBA{red, red, red}},
+	}
+
+	for i, test := range tests {
+		WriteMessage(conn, Message{Channel: test.channel, Command: SetPixelColors, Data: test.data})
+		select {
+		case <-s.Updates():
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for the frame")
+		}
+		for c, want := range test.want {
+			result := s.Frame(uint8(c))
+			if want == (ic.RGBA{}) {
+				if len(result) != 0 {
+					t.Errorf("Message %v channel %v: Wanted an empty frame, got: %v", i, c, result)
+				}
+				continue
+			}
+			if len(result) != 1 || result[0].GetColor() != want {
+				t.Errorf("Message %v channel %v: Wanted %v, got: %v", i, c, want, result)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// ChannelOrder defines the order the red, green and blue channels are sent in
+type ChannelOrder int
+
+const (
+	// RGB sends red, green, blue
+	RGB ChannelOrder = iota
+	// RBG sends red, blue, green
+	RBG
+	// GRB sends green, red, blue
+	GRB
+	// GBR sends green, blue, red
+	GBR
+	// BRG sends blue, red, green
+	BRG
+	// BGR sends blue, green, red
+	BGR
+)
+
+func (o ChannelOrder) String() string {
+	if o < RGB || o > BGR {
+		return fmt.Sprintf("ChannelOrder(%d)", int(o))
+	}
+	return [...]string{"RGB", "RBG", "GRB", "GBR", "BRG", "BGR"}[o]
+}
+
+// Put writes the three channel values into buf in the channel order
+func (o ChannelOrder) Put(buf []byte, r uint8, g uint8, b uint8) {
+	switch o {
+	case RBG:
+		buf[0], buf[1], buf[2] = r, b, g
+	case GRB:
+		buf[0], buf[1], buf[2] = g, r, b
+	case GBR:
+		buf[0], buf[1], buf[2] = g, b, r
+	case BRG:
+		buf[0], buf[1], buf[2] = b, r, g
+	case BGR:
+		buf[0], buf[1], buf[2] = b, g, r
+	default:
+		buf[0], buf[1], buf[2] = r, g, b
+	}
+}
+
+// Channels returns the red, green and blue values of the color scaled by its brightness (alpha)
+func Channels(c *color.Color) (r uint8, g uint8, b uint8) {
+	rgba := c.GetColor()
+	return scale(rgba.R, rgba.A), scale(rgba.G, rgba.A), scale(rgba.B, rgba.A)
+}
+
+// scale multiplies a channel value by a brightness level, rounding to the nearest value
+func scale(value uint8, brightness uint8) uint8 {
+	return uint8((int(value)*int(brightness) + math.MaxUint8/2) / math.MaxUint8)
+}
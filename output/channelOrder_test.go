@@ -0,0 +1,55 @@
+package output
+
+import (
+	"bytes"
+	ic "image/color"
+	"testing"
+
+	"github.com/gazek/color-blender/color"
+)
+
+func TestChannelOrderPut(t *testing.T) {
+	tests := []struct {
+		order ChannelOrder
+		want  []byte
+	}{
+		{RGB, []byte{1, 2, 3}},
+		{RBG, []byte{1, 3, 2}},
+		{GRB, []byte{2, 1, 3}},
+		{GBR, []byte{2, 3, 1}},
+		{BRG, []byte{3, 1, 2}},
+		{BGR, []byte{3, 2, 1}},
+	}
+
+	for _, test := range tests {
+		buf := make([]byte, 3)
+		test.order.Put(buf, 1, 2, 3)
+		if !bytes.Equal(buf, test.want) {
+			t.Errorf("%v: Wanted %v, got: %v", test.order, test.want, buf)
+		}
+		if test.order.String() == "" {
+			t.Errorf("Missing name for order %d", int(test.order))
+		}
+	}
+	if result := ChannelOrder(9).String(); result != "ChannelOrder(9)" {
+		t.Errorf("Wanted %v, got: %v", "ChannelOrder(9)", result)
+	}
+}
+
+func TestChannels(t *testing.T) {
+	tests := []struct {
+		color ic.RGBA
+		want  []uint8
+	}{
+		{ic.RGBA{R: 255, G: 128, B: 2, A: 255}, []uint8{255, 128, 2}},
+		{ic.RGBA{R: 255, G: 128, B: 2, A: 0}, []uint8{0, 0, 0}},
+		{ic.RGBA{R: 255, G: 128, B: 2, A: 128}, []uint8{128, 64, 1}},
+	}
+
+	for _, test := range tests {
+		r, g, b := Channels(color.NewColor(test.color))
+		if r != test.want[0] || g != test.want[1] || b != test.want[2] {
+			t.Errorf("Wanted %v, got: %v", test.want, []uint8{r, g, b})
+		}
+	}
+}
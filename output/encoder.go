@@ -0,0 +1,191 @@
+package output
+
+import (
+	"errors"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// ErrShortBuffer is returned when the buffer passed to an Encoder can't hold the encoded frame
+var ErrShortBuffer = errors.New("buffer too small for encoded frame")
+
+// Encoder packs a frame into the bytes expected by an LED protocol.
+// Each color is scaled by its brightness (alpha) before it is encoded.
+type Encoder interface {
+	// EncodedLen returns the number of bytes needed to encode numPixels pixels
+	EncodedLen(numPixels int) int
+	// Encode writes the frame into buf and returns the number of bytes written
+	Encode(buf []byte, frame []color.Color) (int, error)
+}
+
+// WS2812 encodes three bytes per pixel, the chips expect GRB order
+type WS2812 struct {
+	Order ChannelOrder
+}
+
+// NewWS2812 creates a new WS2812 encoder with the GRB channel order
+func NewWS2812() *WS2812 {
+	return &WS2812{Order: GRB}
+}
+
+// EncodedLen returns the number of bytes needed to encode numPixels pixels
+func (e *WS2812) EncodedLen(numPixels int) int {
+	return 3 * numPixels
+}
+
+// Encode writes the frame into buf and returns the number of bytes written
+func (e *WS2812) Encode(buf []byte, frame []color.Color) (int, error) {
+	if len(buf) < e.EncodedLen(len(frame)) {
+		return 0, ErrShortBuffer
+	}
+	for i := range frame {
+		r, g, b := Channels(&frame[i])
+		e.Order.Put(buf[3*i:], r, g, b)
+	}
+	return e.EncodedLen(len(frame)), nil
+}
+
+// SK6812 encodes four bytes per pixel, three color channels followed by white.
+// The white channel takes over the part of the color shared by all three channels.
+type SK6812 struct {
+	Order ChannelOrder
+}
+
+// NewSK6812 creates a new SK6812 RGBW encoder with the GRB channel order
+func NewSK6812() *SK6812 {
+	return &SK6812{Order: GRB}
+}
+
+// EncodedLen returns the number of bytes needed to encode numPixels pixels
+func (e *SK6812) EncodedLen(numPixels int) int {
+	return 4 * numPixels
+}
+
+// Encode writes the frame into buf and returns the number of bytes written
+func (e *SK6812) Encode(buf []byte, frame []color.Color) (int, error) {
+	if len(buf) < e.EncodedLen(len(frame)) {
+		return 0, ErrShortBuffer
+	}
+	for i := range frame {
+		r, g, b := Channels(&frame[i])
+		// the smallest channel is the white component
+		w := r
+		if g < w {
+			w = g
+		}
+		if b < w {
+			w = b
+		}
+		e.Order.Put(buf[4*i:], r-w, g-w, b-w)
+		buf[4*i+3] = w
+	}
+	return e.EncodedLen(len(frame)), nil
+}
+
+// APA102 encodes APA102 and SK9822 frames: a start frame, four bytes per pixel and an end frame.
+// Each pixel starts with a 5 bit global brightness.
+type APA102 struct {
+	Order ChannelOrder
+	// GlobalBrightness is the 5 bit (0-31) brightness sent with every pixel
+	GlobalBrightness uint8
+	// SK9822 adds the extra reset frame those chips need to latch the data
+	SK9822 bool
+}
+
+// NewAPA102 creates a new APA102 encoder with the BGR channel order and full global brightness
+func NewAPA102() *APA102 {
+	return &APA102{Order: BGR, GlobalBrightness: 31}
+}
+
+// EncodedLen returns the number of bytes needed to encode numPixels pixels
+func (e *APA102) EncodedLen(numPixels int) int {
+	n := 4 + 4*numPixels + e.endFrameLen(numPixels)
+	if e.SK9822 {
+		n += 4
+	}
+	return n
+}
+
+// endFrameLen returns the length of the end frame, which needs one bit for every two pixels
+func (e *APA102) endFrameLen(numPixels int) int {
+	n := (numPixels + 15) / 16
+	if n < 4 {
+		n = 4
+	}
+	return n
+}
+
+// Encode writes the frame into buf and returns the number of bytes written
+func (e *APA102) Encode(buf []byte, frame []color.Color) (int, error) {
+	n := e.EncodedLen(len(frame))
+	if len(buf) < n {
+		return 0, ErrShortBuffer
+	}
+	// start frame
+	pos := 0
+	for ; pos < 4; pos++ {
+		buf[pos] = 0x00
+	}
+	// pixels
+	brightness := 0xe0 | (e.GlobalBrightness & 0x1f)
+	for i := range frame {
+		r, g, b := Channels(&frame[i])
+		buf[pos] = brightness
+		e.Order.Put(buf[pos+1:], r, g, b)
+		pos += 4
+	}
+	// reset frame
+	if e.SK9822 {
+		for end := pos + 4; pos < end; pos++ {
+			buf[pos] = 0x00
+		}
+	}
+	// end frame
+	for ; pos < n; pos++ {
+		buf[pos] = 0xff
+	}
+	return n, nil
+}
+
+// LPD8806 encodes three 7 bit channels per pixel, each with the high bit set, followed by a zero latch
+type LPD8806 struct {
+	Order ChannelOrder
+}
+
+// NewLPD8806 creates a new LPD8806 encoder with the GRB channel order
+func NewLPD8806() *LPD8806 {
+	return &LPD8806{Order: GRB}
+}
+
+// EncodedLen returns the number of bytes needed to encode numPixels pixels
+func (e *LPD8806) EncodedLen(numPixels int) int {
+	return 3*numPixels + e.latchLen(numPixels)
+}
+
+// latchLen returns the length of the latch, one zero byte for every 32 pixels
+func (e *LPD8806) latchLen(numPixels int) int {
+	n := (numPixels + 31) / 32
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// Encode writes the frame into buf and returns the number of bytes written
+func (e *LPD8806) Encode(buf []byte, frame []color.Color) (int, error) {
+	n := e.EncodedLen(len(frame))
+	if len(buf) < n {
+		return 0, ErrShortBuffer
+	}
+	pos := 0
+	for i := range frame {
+		r, g, b := Channels(&frame[i])
+		e.Order.Put(buf[pos:], 0x80|r>>1, 0x80|g>>1, 0x80|b>>1)
+		pos += 3
+	}
+	// latch
+	for ; pos < n; pos++ {
+		buf[pos] = 0x00
+	}
+	return n, nil
+}
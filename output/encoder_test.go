@@ -0,0 +1,83 @@
+package output
+
+import (
+	"bytes"
+	ic "image/color"
+	"testing"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// testFrame is a two pixel frame, the second pixel has half brightness
+func testFrame() []color.Color {
+	return []color.Color{
+		*color.NewColor(ic.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff}),
+		*color.NewColor(ic.RGBA{R: 0xff, G: 0x80, B: 0x40, A: 0x80}),
+	}
+}
+
+func TestEncoders(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoder Encoder
+		want    []byte
+	}{
+		{"ws2812", NewWS2812(), []byte{0x20, 0x10, 0x30, 0x40, 0x80, 0x20}},
+		{"ws2812 rgb", &WS2812{Order: RGB}, []byte{0x10, 0x20, 0x30, 0x80, 0x40, 0x20}},
+		{"sk6812", NewSK6812(), []byte{0x10, 0x00, 0x20, 0x10, 0x20, 0x60, 0x00, 0x20}},
+		{"apa102", &APA102{Order: BGR, GlobalBrightness: 0x1f}, []byte{
+			0x00, 0x00, 0x00, 0x00,
+			0xff, 0x30, 0x20, 0x10,
+			0xff, 0x20, 0x40, 0x80,
+			0xff, 0xff, 0xff, 0xff,
+		}},
+		{"sk9822", &APA102{Order: BGR, GlobalBrightness: 0x21, SK9822: true}, []byte{
+			0x00, 0x00, 0x00, 0x00,
+			0xe1, 0x30, 0x20, 0x10,
+			0xe1, 0x20, 0x40, 0x80,
+			0x00, 0x00, 0x00, 0x00,
+			0xff, 0xff, 0xff, 0xff,
+		}},
+		{"lpd8806", NewLPD8806(), []byte{0x90, 0x88, 0x98, 0xa0, 0xc0, 0x90, 0x00}},
+	}
+
+	for _, test := range tests {
+		frame := testFrame()
+		if n := test.encoder.EncodedLen(len(frame)); n != len(test.want) {
+			t.Errorf("%v: Wanted length %v, got: %v", test.name, len(test.want), n)
+		}
+		buf := make([]byte, len(test.want)+2)
+		n, err := test.encoder.Encode(buf, frame)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !bytes.Equal(buf[:n], test.want) {
+			t.Errorf("%v: Wanted % x, got: % x", test.name, test.want, buf[:n])
+		}
+		// a buffer that is too small should fail
+		if _, err := test.encoder.Encode(make([]byte, n-1), frame); err != ErrShortBuffer {
+			t.Errorf("%v: Wanted %v, got: %v", test.name, ErrShortBuffer, err)
+		}
+	}
+}
+
+func TestEncodedLen(t *testing.T) {
+	tests := []struct {
+		name      string
+		encoder   Encoder
+		numPixels int
+		want      int
+	}{
+		{"apa102 long", NewAPA102(), 100, 4 + 400 + 7},
+		{"apa102 empty", NewAPA102(), 0, 8},
+		{"lpd8806 long", NewLPD8806(), 64, 192 + 2},
+		{"lpd8806 empty", NewLPD8806(), 0, 1},
+	}
+
+	for _, test := range tests {
+		if result := test.encoder.EncodedLen(test.numPixels); result != test.want {
+			t.Errorf("%v: Wanted %v, got: %v", test.name, test.want, result)
+		}
+	}
+}
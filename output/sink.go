@@ -0,0 +1,39 @@
+package output
+
+import (
+	"io"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// Sink receives rendered frames, it matches the player.Sink interface.
+// A sink must not change the frame it is given or keep it after WriteFrame returns.
+type Sink interface {
+	WriteFrame(frame []color.Color) error
+}
+
+// WriterSink encodes each frame and writes the bytes to an io.Writer, such as a SPI device
+type WriterSink struct {
+	writer  io.Writer
+	encoder Encoder
+	buf     []byte
+}
+
+// NewWriterSink creates a new WriterSink
+func NewWriterSink(w io.Writer, e Encoder) *WriterSink {
+	return &WriterSink{writer: w, encoder: e}
+}
+
+// WriteFrame encodes the frame and writes it in a single call to the writer
+func (s *WriterSink) WriteFrame(frame []color.Color) error {
+	// grow the buffer if the frame got bigger
+	if n := s.encoder.EncodedLen(len(frame)); len(s.buf) < n {
+		s.buf = make([]byte, n)
+	}
+	n, err := s.encoder.Encode(s.buf, frame)
+	if err != nil {
+		return err
+	}
+	_, err = s.writer.Write(s.buf[:n])
+	return err
+}
@@ -0,0 +1,31 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWriterSink(t *testing.T) {
+	var out bytes.Buffer
+	s := NewWriterSink(&out, NewWS2812())
+	if err := s.WriteFrame(testFrame()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.WriteFrame(testFrame()[:1]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []byte{0x20, 0x10, 0x30, 0x40, 0x80, 0x20, 0x20, 0x10, 0x30}
+	if !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("Wanted % x, got: % x", want, out.Bytes())
+	}
+	if err := NewWriterSink(failingWriter{}, NewWS2812()).WriteFrame(testFrame()); err == nil {
+		t.Error("Wanted the writer error")
+	}
+}
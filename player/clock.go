@@ -0,0 +1,20 @@
+package player
+
+import "time"
+
+// Clock provides the time to a Player, so tests can run without real sleeps
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// realClock is a Clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
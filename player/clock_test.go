@@ -0,0 +1,38 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when it sleeps or is told to
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestRealClock(t *testing.T) {
+	c := realClock{}
+	start := c.Now()
+	c.Sleep(time.Millisecond)
+	if elapsed := c.Now().Sub(start); elapsed < time.Millisecond {
+		t.Errorf("Wanted at least %v, got: %v", time.Millisecond, elapsed)
+	}
+}
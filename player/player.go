@@ -0,0 +1,255 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gazek/color-blender/blender"
+	"github.com/gazek/color-blender/color"
+)
+
+// Animation is anything with a step position that can be rendered into a frame, such as a spatial.Renderer
+type Animation interface {
+	ResetStep()
+	AdvanceStep(numSteps int)
+	Frame() []color.Color
+}
+
+// Sink receives each frame rendered by a Player.
+// A sink must not change the frame it is given or keep it after WriteFrame returns.
+type Sink interface {
+	WriteFrame(frame []color.Color) error
+}
+
+// Source is anything with a step position that produces a single color, such as a Blender or a Cursor
+type Source interface {
+	ResetStep()
+	AdvanceStep(numSteps int)
+	GetColor() *color.Color
+}
+
+// single adapts a Source into a one pixel Animation
+type single struct {
+	Source
+	frame []color.Color
+}
+
+// Single creates a one pixel Animation from a Source
+func Single(s Source) Animation {
+	return &single{Source: s, frame: make([]color.Color, 1)}
+}
+
+func (s *single) Frame() []color.Color {
+	s.frame[0] = *s.GetColor()
+	return s.frame
+}
+
+// Player advances an Animation in real time and delivers its frames to a Sink
+type Player struct {
+	mu             sync.Mutex
+	animation      Animation
+	sink           Sink
+	clock          Clock
+	frameInterval  time.Duration
+	stepsPerSecond float64
+	speed          float64
+	brightness     uint8
+	dimmingCurve   blender.DimmingCurve
+	paused         bool
+	// position is the number of steps advanced since the last reset or seek
+	position int
+	// pending holds the fraction of a step carried over between ticks
+	pending   float64
+	lastTick  time.Time
+	lastFrame []color.Color
+}
+
+// NewPlayer creates a new Player that renders fps frames per second and advances stepsPerSecond steps per second
+func NewPlayer(animation Animation, sink Sink, fps float64, stepsPerSecond float64) (*Player, error) {
+	if fps <= 0 {
+		return nil, errors.New("frame rate must be greater than zero")
+	}
+	if stepsPerSecond < 0 {
+		return nil, errors.New("step rate must not be negative")
+	}
+	return &Player{
+		animation:      animation,
+		sink:           sink,
+		clock:          realClock{},
+		frameInterval:  time.Duration(float64(time.Second) / fps),
+		stepsPerSecond: stepsPerSecond,
+		speed:          1,
+		brightness:     math.MaxUint8,
+	}, nil
+}
+
+// SetClock replaces the clock used to time frames
+func (p *Player) SetClock(c Clock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clock = c
+	p.lastTick = time.Time{}
+}
+
+// SetAnimation replaces the animation being played and starts it from step zero
+func (p *Player) SetAnimation(animation Animation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.animation = animation
+	p.seek(0)
+}
+
+// Pause stops the step position from advancing, frames are still delivered
+func (p *Player) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+}
+
+// Resume continues advancing the step position from where it was paused
+func (p *Player) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = false
+}
+
+// IsPaused reports whether the player is paused
+func (p *Player) IsPaused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+// SetSpeed sets the playback speed as a multiple of the configured step rate
+func (p *Player) SetSpeed(speed float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.speed = speed
+}
+
+// GetSpeed returns the playback speed
+func (p *Player) GetSpeed() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.speed
+}
+
+// SetBrightness sets the master brightness, which scales the brightness (alpha) of every pixel after
+// passing through the dimming curve. This is the dimmer used by the control API.
+func (p *Player) SetBrightness(brightness uint8) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.brightness = brightness
+}
+
+// GetBrightness returns the master brightness
+func (p *Player) GetBrightness() uint8 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.brightness
+}
+
+// SetDimmingCurve sets the curve the master brightness is passed through, nil means blender.LinearCurve
+func (p *Player) SetDimmingCurve(curve blender.DimmingCurve) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dimmingCurve = curve
+}
+
+// LastFrame returns a copy of the most recently delivered frame
+func (p *Player) LastFrame() []color.Color {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	frame := make([]color.Color, len(p.lastFrame))
+	copy(frame, p.lastFrame)
+	return frame
+}
+
+// Seek moves the animation to the given step position
+func (p *Player) Seek(step int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seek(step)
+}
+
+// GetPosition returns the number of steps advanced since the last reset or seek
+func (p *Player) GetPosition() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.position
+}
+
+// Tick advances the animation by the steps that are due since the previous tick and delivers one frame.
+// Any frames that were missed are made up for by advancing multiple steps.
+func (p *Player) Tick() error {
+	p.mu.Lock()
+	frame := p.tick()
+	sink := p.sink
+	p.mu.Unlock()
+	// deliver the frame outside the lock so a slow sink doesn't block the controls
+	if sink == nil {
+		return nil
+	}
+	return sink.WriteFrame(frame)
+}
+
+// Run ticks the player at the configured frame rate until the context is cancelled or the sink returns an error
+func (p *Player) Run(ctx context.Context) error {
+	p.mu.Lock()
+	clock := p.clock
+	next := clock.Now()
+	p.mu.Unlock()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := p.Tick(); err != nil {
+			return err
+		}
+		// schedule the next frame, dropping any frames we are already too late for
+		next = next.Add(p.frameInterval)
+		now := clock.Now()
+		if now.After(next) {
+			next = now
+		}
+		clock.Sleep(next.Sub(now))
+	}
+}
+
+// tick advances the animation and renders a frame, the caller must hold the lock
+func (p *Player) tick() []color.Color {
+	now := p.clock.Now()
+	// the first tick only starts the clock
+	if !p.lastTick.IsZero() && !p.paused {
+		p.pending += now.Sub(p.lastTick).Seconds() * p.stepsPerSecond * p.speed
+		// only advance by whole steps and carry the remainder
+		numSteps := int(p.pending)
+		p.pending -= float64(numSteps)
+		if numSteps != 0 {
+			p.animation.AdvanceStep(numSteps)
+			p.position += numSteps
+		}
+	}
+	p.lastTick = now
+	frame := p.animation.Frame()
+	// apply the master brightness
+	if p.brightness < math.MaxUint8 {
+		for i := range frame {
+			frame[i].SetBrightness(blender.Dim(frame[i].GetColor().A, p.brightness, p.dimmingCurve))
+		}
+	}
+	// keep our own copy, the sink is handed the frame outside the lock
+	p.lastFrame = append(p.lastFrame[:0], frame...)
+	return frame
+}
+
+// seek moves the animation to the given step position, the caller must hold the lock
+func (p *Player) seek(step int) {
+	p.animation.ResetStep()
+	p.animation.AdvanceStep(step)
+	p.position = step
+	p.pending = 0
+}
@@ -0,0 +1,236 @@
+package player
+
+import (
+	"context"
+	"errors"
+	ic "image/color"
+	"testing"
+	"time"
+
+	"github.com/gazek/color-blender/blender"
+	"github.com/gazek/color-blender/color"
+	"github.com/gazek/color-blender/transfunc"
+)
+
+// countingAnimation renders its step position as the red component of a single pixel
+type countingAnimation struct {
+	step int
+}
+
+func (a *countingAnimation) ResetStep() {
+	a.step = 0
+}
+
+func (a *countingAnimation) AdvanceStep(numSteps int) {
+	a.step += numSteps
+}
+
+func (a *countingAnimation) Frame() []color.Color {
+	return []color.Color{*color.NewColor(ic.RGBA{R: uint8(a.step)})}
+}
+
+// recordingSink keeps the red component of every frame it receives
+type recordingSink struct {
+	frames []uint8
+	// cancel is called once limit frames have been received
+	limit  int
+	cancel func()
+	err    error
+}
+
+func (s *recordingSink) WriteFrame(frame []color.Color) error {
+	s.frames = append(s.frames, frame[0].GetColor().R)
+	if s.cancel != nil && len(s.frames) >= s.limit {
+		s.cancel()
+	}
+	return s.err
+}
+
+func TestNewPlayerErrors(t *testing.T) {
+	if _, err := NewPlayer(&countingAnimation{}, nil, 0, 1); err == nil {
+		t.Error("Wanted an error for a zero frame rate")
+	}
+	if _, err := NewPlayer(&countingAnimation{}, nil, 1, -1); err == nil {
+		t.Error("Wanted an error for a negative step rate")
+	}
+}
+
+func TestTick(t *testing.T) {
+	tests := []struct {
+		name     string
+		elapsed  []time.Duration
+		speed    float64
+		wantStep []uint8
+	}{
+		{"on time", []time.Duration{0, 100 * time.Millisecond, 100 * time.Millisecond}, 1, []uint8{0, 1, 2}},
+		{"dropped frames", []time.Duration{0, 100 * time.Millisecond, 300 * time.Millisecond}, 1, []uint8{0, 1, 4}},
+		{"fractional steps", []time.Duration{0, 50 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}, 1, []uint8{0, 0, 1, 1}},
+		{"double speed", []time.Duration{0, 100 * time.Millisecond}, 2, []uint8{0, 2}},
+	}
+
+	for _, test := range tests {
+		clock := newFakeClock()
+		sink := &recordingSink{}
+		// 10 steps per second
+		p, _ := NewPlayer(&countingAnimation{}, sink, 10, 10)
+		p.SetClock(clock)
+		p.SetSpeed(test.speed)
+		for _, d := range test.elapsed {
+			clock.advance(d)
+			if err := p.Tick(); err != nil {
+				t.Errorf("%v: unexpected error: %v", test.name, err)
+			}
+		}
+		for i := range test.wantStep {
+			if sink.frames[i] != test.wantStep[i] {
+				t.Errorf("%v frame %v: Wanted %v, got: %v", test.name, i, test.wantStep[i], sink.frames[i])
+			}
+		}
+	}
+}
+
+func TestPauseResumeSeek(t *testing.T) {
+	clock := newFakeClock()
+	sink := &recordingSink{}
+	p, _ := NewPlayer(&countingAnimation{}, sink, 10, 10)
+	p.SetClock(clock)
+	p.Tick()
+	clock.advance(time.Second)
+	p.Pause()
+	if !p.IsPaused() {
+		t.Error("Wanted the player to be paused")
+	}
+	p.Tick()
+	clock.advance(time.Second)
+	p.Tick()
+	p.Resume()
+	clock.advance(200 * time.Millisecond)
+	p.Tick()
+	p.Seek(42)
+	p.Tick()
+	want := []uint8{0, 0, 0, 2, 42}
+	for i := range want {
+		if sink.frames[i] != want[i] {
+			t.Errorf("Frame %v: Wanted %v, got: %v", i, want[i], sink.frames[i])
+		}
+	}
+	if p.GetPosition() != 42 {
+		t.Errorf("Wanted: %v, found: %v", 42, p.GetPosition())
+	}
+	if p.GetSpeed() != 1 {
+		t.Errorf("Wanted: %v, found: %v", 1, p.GetSpeed())
+	}
+}
+
+func TestRun(t *testing.T) {
+	clock := newFakeClock()
+	ctx, cancel := context.WithCancel(context.Background())
+	sink := &recordingSink{limit: 5, cancel: cancel}
+	p, _ := NewPlayer(&countingAnimation{}, sink, 10, 20)
+	p.SetClock(clock)
+	if err := p.Run(ctx); err != context.Canceled {
+		t.Errorf("Wanted %v, got: %v", context.Canceled, err)
+	}
+	want := []uint8{0, 2, 4, 6, 8}
+	for i := range want {
+		if sink.frames[i] != want[i] {
+			t.Errorf("Frame %v: Wanted %v, got: %v", i, want[i], sink.frames[i])
+		}
+	}
+	for i := range clock.sleeps {
+		if clock.sleeps[i] != 100*time.Millisecond {
+			t.Errorf("Sleep %v: Wanted %v, got: %v", i, 100*time.Millisecond, clock.sleeps[i])
+		}
+	}
+}
+
+func TestRunSinkError(t *testing.T) {
+	sinkErr := errors.New("sink failed")
+	p, _ := NewPlayer(&countingAnimation{}, &recordingSink{err: sinkErr}, 10, 10)
+	p.SetClock(newFakeClock())
+	if err := p.Run(context.Background()); err != sinkErr {
+		t.Errorf("Wanted %v, got: %v", sinkErr, err)
+	}
+}
+
+func TestSingle(t *testing.T) {
+	b := &blender.Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{}, ic.RGBA{R: 250}, transfunc.AllAtOnce, func(x float32) float32 { return x }, 5, []float32{0, 1}))
+	clock := newFakeClock()
+	sink := &recordingSink{}
+	p, _ := NewPlayer(Single(b), sink, 10, 10)
+	p.SetClock(clock)
+	p.Tick()
+	clock.advance(200 * time.Millisecond)
+	p.Tick()
+	want := []uint8{0, 100}
+	for i := range want {
+		if sink.frames[i] != want[i] {
+			t.Errorf("Frame %v: Wanted %v, got: %v", i, want[i], sink.frames[i])
+		}
+	}
+	p.SetAnimation(&countingAnimation{})
+	p.Tick()
+	if sink.frames[2] != 0 {
+		t.Errorf("Wanted: %v, found: %v", 0, sink.frames[2])
+	}
+}
+
+func TestBrightnessAndLastFrame(t *testing.T) {
+	b := &blender.Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{R: 255}, ic.RGBA{R: 255}, transfunc.AllAtOnce, func(x float32) float32 { return 0 }, 1, nil))
+	b.AppendBrightnessFunc(transfunc.NewBrightnessFunc(func(x float32) float32 { return 1 }, 1, nil))
+	p, _ := NewPlayer(Single(b), nil, 10, 10)
+	p.SetClock(newFakeClock())
+	if len(p.LastFrame()) != 0 {
+		t.Error("Wanted no frame before the first tick")
+	}
+	p.SetBrightness(128)
+	if p.GetBrightness() != 128 {
+		t.Errorf("Wanted: %v, found: %v", 128, p.GetBrightness())
+	}
+	p.Tick()
+	frame := p.LastFrame()
+	if len(frame) != 1 || frame[0].GetColor().A != 128 {
+		t.Errorf("Wanted alpha %v, got: %v", 128, frame)
+	}
+}
+
+// zeroingSink blacks out every frame it receives, like a sink that dims frames before sending them
+type zeroingSink struct{}
+
+func (s zeroingSink) WriteFrame(frame []color.Color) error {
+	for i := range frame {
+		frame[i].SetColor(ic.RGBA{})
+	}
+	return nil
+}
+
+// run with -race to check that the sink doesn't share the frame returned by LastFrame
+func TestLastFrameWithMutatingSink(t *testing.T) {
+	b := &blender.Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(ic.RGBA{R: 255}, ic.RGBA{R: 255}, transfunc.AllAtOnce, func(x float32) float32 { return 0 }, 1, nil))
+	b.AppendBrightnessFunc(transfunc.NewBrightnessFunc(func(x float32) float32 { return 1 }, 1, nil))
+	p, _ := NewPlayer(Single(b), zeroingSink{}, 10, 10)
+	p.SetClock(newFakeClock())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			p.Tick()
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			// the player reports the rendered colors, not the ones the sink changed
+			frame := p.LastFrame()
+			if len(frame) != 1 || frame[0].GetColor().R != 255 {
+				t.Errorf("Wanted red %v, got: %v", 255, frame)
+			}
+			return
+		default:
+			p.LastFrame()
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package power
+
+import (
+	"fmt"
+	ic "image/color"
+	"math"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// Mode defines how a frame is dimmed to stay within the budget
+type Mode int
+
+const (
+	// ScaleChannels scales the red, green and blue values of every pixel
+	ScaleChannels Mode = iota
+	// ScaleBrightness scales only the brightness (alpha) of every pixel
+	ScaleBrightness
+)
+
+func (m Mode) String() string {
+	if m < ScaleChannels || m > ScaleBrightness {
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+	return [...]string{"ScaleChannels", "ScaleBrightness"}[m]
+}
+
+// Limiter estimates the current drawn by a frame and dims it to stay within a budget.
+// Limit is not safe for concurrent use, a LimitSink serializes its calls.
+type Limiter struct {
+	// MilliampsPerChannel is the current drawn by each of the red, green and blue channels at full value
+	MilliampsPerChannel [3]float64
+	// IdleMilliamps is the current drawn by each pixel when it is off
+	IdleMilliamps float64
+	// BudgetMilliamps is the most current the whole frame may draw
+	BudgetMilliamps float64
+	Mode            Mode
+	// Knee is the fraction (0-1) of the budget at which dimming starts. Zero is a hard limit
+	// at the budget, higher values start dimming earlier and approach the budget smoothly.
+	// Values outside 0-1 are clamped.
+	Knee float64
+	// limited holds the dimmed frame, it is reused by every call to Limit
+	limited []color.Color
+}
+
+// NewLimiter creates a new Limiter with typical WS2812 values, 20mA per channel and 1mA idle
+func NewLimiter(budgetMilliamps float64) *Limiter {
+	return &Limiter{
+		MilliampsPerChannel: [3]float64{20, 20, 20},
+		IdleMilliamps:       1,
+		BudgetMilliamps:     budgetMilliamps,
+	}
+}
+
+// Estimate returns the current in milliamps the frame would draw, each color is scaled by its brightness (alpha)
+func (l *Limiter) Estimate(frame []color.Color) float64 {
+	return l.IdleMilliamps*float64(len(frame)) + l.activeMilliamps(frame)
+}
+
+// Limit returns a dimmed copy of the frame that stays within the budget and the scale that was applied.
+// The frame is left unchanged. The copy belongs to the Limiter and is only valid until the next call.
+func (l *Limiter) Limit(frame []color.Color) ([]color.Color, float64) {
+	limited := append(l.limited[:0], frame...)
+	l.limited = limited
+	scale := l.getScale(frame)
+	if scale >= 1 {
+		return limited, 1
+	}
+	for i := range limited {
+		c := limited[i].GetColor()
+		switch l.Mode {
+		case ScaleBrightness:
+			limited[i].SetBrightness(scaleValue(c.A, scale))
+		default:
+			limited[i].SetColor(ic.RGBA{
+				R: scaleValue(c.R, scale),
+				G: scaleValue(c.G, scale),
+				B: scaleValue(c.B, scale),
+				A: c.A,
+			})
+		}
+	}
+	return limited, scale
+}
+
+// getScale calculates the scale needed to bring the frame within the budget
+func (l *Limiter) getScale(frame []color.Color) float64 {
+	active := l.activeMilliamps(frame)
+	if active == 0 {
+		return 1
+	}
+	// the idle current can't be dimmed, so only the rest of the budget is available
+	available := l.BudgetMilliamps - l.IdleMilliamps*float64(len(frame))
+	if available <= 0 {
+		return 0
+	}
+	knee := math.Max(0, math.Min(1, l.Knee)) * available
+	if active <= knee {
+		return 1
+	}
+	// hard limit
+	if knee <= 0 {
+		return math.Min(1, available/active)
+	}
+	// above the knee the current approaches the budget exponentially
+	headroom := available - knee
+	limited := knee + headroom*(1-math.Exp(-(active-knee)/headroom))
+	return limited / active
+}
+
+// activeMilliamps returns the current drawn by the lit channels of the frame
+func (l *Limiter) activeMilliamps(frame []color.Color) float64 {
+	var total float64
+	for i := range frame {
+		c := frame[i].GetColor()
+		brightness := float64(c.A) / math.MaxUint8
+		total += brightness * (float64(c.R)*l.MilliampsPerChannel[0] +
+			float64(c.G)*l.MilliampsPerChannel[1] +
+			float64(c.B)*l.MilliampsPerChannel[2]) / math.MaxUint8
+	}
+	return total
+}
+
+// scaleValue scales a value, rounding down so the frame never ends up over the budget
+func scaleValue(value uint8, scale float64) uint8 {
+	return uint8(math.Floor(float64(value) * scale))
+}
@@ -0,0 +1,125 @@
+package power
+
+import (
+	ic "image/color"
+	"math"
+	"testing"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// whiteFrame creates a frame of numPixels full white pixels
+func whiteFrame(numPixels int) []color.Color {
+	frame := make([]color.Color, numPixels)
+	for i := range frame {
+		frame[i] = *color.NewColor(ic.RGBA{R: 255, G: 255, B: 255, A: 255})
+	}
+	return frame
+}
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		color ic.RGBA
+		want  float64
+	}{
+		{ic.RGBA{R: 255, G: 255, B: 255, A: 255}, 61},
+		{ic.RGBA{R: 255, A: 255}, 21},
+		{ic.RGBA{R: 255, G: 255, B: 255, A: 0}, 1},
+		{ic.RGBA{R: 255, G: 255, B: 255, A: 51}, 13},
+	}
+
+	l := NewLimiter(1000)
+	for _, test := range tests {
+		frame := []color.Color{*color.NewColor(test.color)}
+		if result := l.Estimate(frame); math.Abs(result-test.want) > 1e-9 {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		budget    float64
+		mode      Mode
+		knee      float64
+		numPixels int
+		wantScale float64
+		wantColor ic.RGBA
+	}{
+		{"under budget", 1000, ScaleChannels, 0, 10, 1, ic.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		{"hard limit channels", 310, ScaleChannels, 0, 10, 0.5, ic.RGBA{R: 127, G: 127, B: 127, A: 255}},
+		{"hard limit brightness", 310, ScaleBrightness, 0, 10, 0.5, ic.RGBA{R: 255, G: 255, B: 255, A: 127}},
+		{"idle exceeds budget", 5, ScaleChannels, 0, 10, 0, ic.RGBA{A: 255}},
+		{"below knee", 1000, ScaleChannels, 0.8, 10, 1, ic.RGBA{R: 255, G: 255, B: 255, A: 255}},
+	}
+
+	for _, test := range tests {
+		l := NewLimiter(test.budget)
+		l.Mode = test.mode
+		l.Knee = test.knee
+		frame := whiteFrame(test.numPixels)
+		limited, scale := l.Limit(frame)
+		if math.Abs(scale-test.wantScale) > 1e-9 {
+			t.Errorf("%v: Wanted scale %v, got: %v", test.name, test.wantScale, scale)
+		}
+		if result := limited[0].GetColor(); result != test.wantColor {
+			t.Errorf("%v: Wanted %v, got: %v", test.name, test.wantColor, result)
+		}
+		// the input frame is left alone
+		if result := frame[0].GetColor(); result != (ic.RGBA{R: 255, G: 255, B: 255, A: 255}) {
+			t.Errorf("%v: Wanted the frame unchanged, got: %v", test.name, result)
+		}
+		if estimate := l.Estimate(limited); estimate > math.Max(test.budget, l.IdleMilliamps*float64(test.numPixels)) {
+			t.Errorf("%v: Wanted at most %v mA, got: %v", test.name, test.budget, estimate)
+		}
+	}
+}
+
+func TestSoftKnee(t *testing.T) {
+	l := NewLimiter(510)
+	l.Knee = 0.5
+	// 500mA is available after the idle current, dimming starts at 250mA
+	var last float64
+	for _, numPixels := range []int{4, 5, 8, 20, 100} {
+		l.BudgetMilliamps = 500 + float64(numPixels)
+		frame := whiteFrame(numPixels)
+		active := l.Estimate(frame) - float64(numPixels)
+		_, scale := l.Limit(frame)
+		limited := active * scale
+		if limited > 500 {
+			t.Errorf("%v pixels: Wanted at most %v mA, got: %v", numPixels, 500, limited)
+		}
+		if active > 250 && scale >= 1 {
+			t.Errorf("%v pixels: Wanted dimming above the knee, got scale %v", numPixels, scale)
+		}
+		// more load should always mean more current, just less of it
+		if limited < last {
+			t.Errorf("%v pixels: Wanted at least %v mA, got: %v", numPixels, last, limited)
+		}
+		last = limited
+	}
+}
+
+func TestKneeOutOfRange(t *testing.T) {
+	for _, knee := range []float64{-1, -0.5, 1, 1.5, 10} {
+		l := NewLimiter(310)
+		l.Knee = knee
+		for _, numPixels := range []int{1, 5, 10, 50} {
+			frame := whiteFrame(numPixels)
+			limited, scale := l.Limit(frame)
+			if scale > 1 || scale < 0 {
+				t.Errorf("Knee %v, %v pixels: Wanted a scale within 0-1, got: %v", knee, numPixels, scale)
+			}
+			if estimate := l.Estimate(limited); estimate > math.Max(l.BudgetMilliamps, l.IdleMilliamps*float64(numPixels)) {
+				t.Errorf("Knee %v, %v pixels: Wanted at most %v mA, got: %v", knee, numPixels, l.BudgetMilliamps, estimate)
+			}
+		}
+	}
+}
+
+func TestModeString(t *testing.T) {
+	if ScaleChannels.String() != "ScaleChannels" || ScaleBrightness.String() != "ScaleBrightness" || Mode(4).String() != "Mode(4)" {
+		t.Errorf("Unexpected mode names: %v, %v, %v", ScaleChannels, ScaleBrightness, Mode(4))
+	}
+}
@@ -0,0 +1,46 @@
+package power
+
+import (
+	"sync"
+
+	"github.com/gazek/color-blender/color"
+)
+
+// Sink is implemented by anything that receives frames, such as the output, dmx and opc senders.
+// A sink must not change the frame it is given or keep it after WriteFrame returns.
+type Sink interface {
+	WriteFrame(frame []color.Color) error
+}
+
+// LimitSink limits each frame before passing it on to the next sink
+type LimitSink struct {
+	limiter *Limiter
+	next    Sink
+	// writeMu serializes writes, the limiter reuses its buffer for every frame
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	scale   float64
+}
+
+// NewLimitSink creates a new LimitSink
+func NewLimitSink(l *Limiter, next Sink) *LimitSink {
+	return &LimitSink{limiter: l, next: next, scale: 1}
+}
+
+// WriteFrame writes a limited copy of the frame to the next sink, the frame itself is left unchanged
+func (s *LimitSink) WriteFrame(frame []color.Color) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	limited, scale := s.limiter.Limit(frame)
+	s.mu.Lock()
+	s.scale = scale
+	s.mu.Unlock()
+	return s.next.WriteFrame(limited)
+}
+
+// LastScale returns the scale applied to the most recent frame
+func (s *LimitSink) LastScale() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scale
+}
@@ -0,0 +1,38 @@
+package power
+
+import (
+	"testing"
+
+	"github.com/gazek/color-blender/color"
+)
+
+type recordingSink struct {
+	frames [][]color.Color
+}
+
+func (s *recordingSink) WriteFrame(frame []color.Color) error {
+	// the frame is only valid during the call
+	s.frames = append(s.frames, append([]color.Color(nil), frame...))
+	return nil
+}
+
+func TestLimitSink(t *testing.T) {
+	next := &recordingSink{}
+	s := NewLimitSink(NewLimiter(310), next)
+	if s.LastScale() != 1 {
+		t.Errorf("Wanted %v, got: %v", 1, s.LastScale())
+	}
+	frame := whiteFrame(10)
+	if err := s.WriteFrame(frame); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.LastScale() != 0.5 {
+		t.Errorf("Wanted %v, got: %v", 0.5, s.LastScale())
+	}
+	if len(next.frames) != 1 || next.frames[0][0].GetColor().R != 127 {
+		t.Errorf("Wanted the limited frame passed on, got: %v", next.frames)
+	}
+	if frame[0].GetColor().R != 255 {
+		t.Errorf("Wanted the frame unchanged, got: %v", frame[0].GetColor())
+	}
+}
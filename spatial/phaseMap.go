@@ -0,0 +1,44 @@
+package spatial
+
+// PhaseMap returns the step offset of a pixel relative to the blender's current step position.
+// Pixels with the same offset show the same color.
+type PhaseMap func(pixel int, numPixels int) int
+
+// Linear delays each pixel by stepsPerPixel steps relative to the previous one,
+// so the pattern travels from the first pixel toward the last
+func Linear(stepsPerPixel int) PhaseMap {
+	return func(pixel int, numPixels int) int {
+		return -pixel * stepsPerPixel
+	}
+}
+
+// Reversed is the same as Linear but the pattern travels from the last pixel toward the first
+func Reversed(stepsPerPixel int) PhaseMap {
+	return func(pixel int, numPixels int) int {
+		return -(numPixels - 1 - pixel) * stepsPerPixel
+	}
+}
+
+// Mirrored delays each pixel by its distance from the center of the strip,
+// so the pattern travels outward from the center toward both ends
+func Mirrored(stepsPerPixel int) PhaseMap {
+	return func(pixel int, numPixels int) int {
+		// work in half pixels so even length strips have a center between two pixels
+		dist := 2*pixel - (numPixels - 1)
+		if dist < 0 {
+			dist = -dist
+		}
+		return -dist * stepsPerPixel / 2
+	}
+}
+
+// Lookup uses the table value for each pixel as its offset.
+// Pixels beyond the end of the table have an offset of zero.
+func Lookup(table []int) PhaseMap {
+	return func(pixel int, numPixels int) int {
+		if pixel >= len(table) {
+			return 0
+		}
+		return table[pixel]
+	}
+}
@@ -0,0 +1,28 @@
+package spatial
+
+import (
+	"testing"
+)
+
+func TestPhaseMaps(t *testing.T) {
+	tests := []struct {
+		name      string
+		phaseMap  PhaseMap
+		numPixels int
+		want      []int
+	}{
+		{"linear", Linear(2), 4, []int{0, -2, -4, -6}},
+		{"reversed", Reversed(2), 4, []int{-6, -4, -2, 0}},
+		{"mirrored odd", Mirrored(2), 5, []int{-4, -2, 0, -2, -4}},
+		{"mirrored even", Mirrored(2), 4, []int{-3, -1, -1, -3}},
+		{"lookup", Lookup([]int{3, 1, 4}), 4, []int{3, 1, 4, 0}},
+	}
+
+	for _, test := range tests {
+		for p := range test.want {
+			if result := test.phaseMap(p, test.numPixels); result != test.want[p] {
+				t.Errorf("%v pixel %v: Wanted %v, got: %v", test.name, p, test.want[p], result)
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package spatial
+
+import "github.com/gazek/color-blender/color"
+
+// Source calculates the color for any step position, such as a blender.Blender, blender.Program,
+// blender.Cursor or layer.Stack. A single Source can be shared by the Renderers of several strips.
+type Source interface {
+	GetPeriod() int
+	GetColorAtStep(step int) *color.Color
+}
+
+// Renderer calculates the colors of a strip of pixels driven by a single Source.
+// The Renderer keeps its own step position, the step position of the Source is not used.
+type Renderer struct {
+	source Source
+	step   int
+	frame  []color.Color
+	// offsets holds each distinct pixel offset and pixels holds the pixels that use it
+	offsets []int
+	pixels  [][]int
+	// cache holds the colors calculated for the current frame keyed by step
+	cache map[int]*color.Color
+}
+
+// NewRenderer creates a new Renderer for numPixels pixels. A nil phaseMap gives every pixel the same color.
+func NewRenderer(source Source, numPixels int, phaseMap PhaseMap) *Renderer {
+	r := &Renderer{
+		source: source,
+		frame:  make([]color.Color, numPixels),
+		cache:  map[int]*color.Color{},
+	}
+	if phaseMap == nil {
+		phaseMap = Linear(0)
+	}
+	// group the pixels by offset so each offset is only calculated once per frame
+	index := map[int]int{}
+	for p := 0; p < numPixels; p++ {
+		offset := phaseMap(p, numPixels)
+		i, ok := index[offset]
+		if !ok {
+			i = len(r.offsets)
+			index[offset] = i
+			r.offsets = append(r.offsets, offset)
+			r.pixels = append(r.pixels, nil)
+		}
+		r.pixels[i] = append(r.pixels[i], p)
+	}
+	return r
+}
+
+// NumPixels returns the number of pixels in a frame
+func (r *Renderer) NumPixels() int {
+	return len(r.frame)
+}
+
+// ResetStep sets the step position to zero
+func (r *Renderer) ResetStep() {
+	r.step = 0
+}
+
+// AdvanceStep changes the step position by the numSteps amount, moving back past zero wraps to the end
+func (r *Renderer) AdvanceStep(numSteps int) {
+	r.step = r.wrap(r.step + numSteps)
+}
+
+// GetStep returns the current step position
+func (r *Renderer) GetStep() int {
+	return r.step
+}
+
+// Frame calculates the color of every pixel for the current step position.
+// The returned slice is reused by the next call to Frame.
+func (r *Renderer) Frame() []color.Color {
+	// offsets that wrap to the same step share a color
+	for k := range r.cache {
+		delete(r.cache, k)
+	}
+	for i, offset := range r.offsets {
+		s := r.wrap(r.step + offset)
+		c, ok := r.cache[s]
+		if !ok {
+			c = r.source.GetColorAtStep(s)
+			r.cache[s] = c
+		}
+		for _, p := range r.pixels[i] {
+			r.frame[p] = *c
+		}
+	}
+	return r.frame
+}
+
+// wrap keeps the step within the period of the source
+func (r *Renderer) wrap(step int) int {
+	period := r.source.GetPeriod()
+	if period <= 0 {
+		return 0
+	}
+	return ((step % period) + period) % period
+}
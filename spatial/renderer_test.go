@@ -0,0 +1,117 @@
+package spatial
+
+import (
+	ic "image/color"
+	"testing"
+
+	"github.com/gazek/color-blender/blender"
+	"github.com/gazek/color-blender/layer"
+	"github.com/gazek/color-blender/transfunc"
+)
+
+// newRampBlender creates a blender whose red component is 50 times the step position
+func newRampBlender() *blender.Blender {
+	b := &blender.Blender{}
+	b.AppendColorFunc(transfunc.NewColorFunc(
+		ic.RGBA{},
+		ic.RGBA{R: 250},
+		transfunc.AllAtOnce,
+		func(x float32) float32 { return x },
+		5,
+		[]float32{0, 1},
+	))
+	return b
+}
+
+func TestRendererFrame(t *testing.T) {
+	tests := []struct {
+		name      string
+		phaseMap  PhaseMap
+		numPixels int
+		step      int
+		want      []uint8
+	}{
+		{"nil map", nil, 3, 2, []uint8{100, 100, 100}},
+		{"linear", Linear(1), 4, 0, []uint8{0, 200, 150, 100}},
+		{"linear advanced", Linear(1), 4, 3, []uint8{150, 100, 50, 0}},
+		{"reversed", Reversed(1), 4, 3, []uint8{0, 50, 100, 150}},
+		{"mirrored", Mirrored(1), 5, 2, []uint8{0, 50, 100, 50, 0}},
+		{"lookup", Lookup([]int{1, 2, 3}), 3, 0, []uint8{50, 100, 150}},
+	}
+
+	for _, test := range tests {
+		r := NewRenderer(newRampBlender(), test.numPixels, test.phaseMap)
+		r.AdvanceStep(test.step)
+		frame := r.Frame()
+		if len(frame) != test.numPixels {
+			t.Errorf("%v: Wanted %v pixels, got: %v", test.name, test.numPixels, len(frame))
+			continue
+		}
+		for p := range frame {
+			if result := frame[p].GetColor().R; result != test.want[p] {
+				t.Errorf("%v pixel %v: Wanted %v, got: %v", test.name, p, test.want[p], result)
+			}
+		}
+	}
+}
+
+func TestRendererResetStep(t *testing.T) {
+	r := NewRenderer(newRampBlender(), 2, Linear(1))
+	r.AdvanceStep(2)
+	r.ResetStep()
+	want := []uint8{0, 200}
+	frame := r.Frame()
+	for p := range want {
+		if result := frame[p].GetColor().R; result != want[p] {
+			t.Errorf("Pixel %v: Wanted %v, got: %v", p, want[p], result)
+		}
+	}
+	if r.NumPixels() != 2 {
+		t.Errorf("Wanted %v, got: %v", 2, r.NumPixels())
+	}
+}
+
+func TestRendererSources(t *testing.T) {
+	b := newRampBlender()
+	p := b.Compile()
+	stack := &layer.Stack{}
+	stack.AppendLayer(layer.NewLayer(blender.NewCursor(p), layer.Normal))
+	tests := []struct {
+		name   string
+		source Source
+	}{
+		{"blender", b},
+		{"program", p},
+		{"cursor", blender.NewCursor(p)},
+		{"stack", stack},
+	}
+
+	want := []uint8{150, 100, 50}
+	for _, test := range tests {
+		r := NewRenderer(test.source, 3, Linear(1))
+		// moving back past zero wraps to the end
+		r.AdvanceStep(-2)
+		if r.GetStep() != 3 {
+			t.Errorf("%v: Wanted step %v, got: %v", test.name, 3, r.GetStep())
+		}
+		frame := r.Frame()
+		for i := range want {
+			if result := frame[i].GetColor().R; result != want[i] {
+				t.Errorf("%v pixel %v: Wanted %v, got: %v", test.name, i, want[i], result)
+			}
+		}
+	}
+}
+
+func TestRenderersShareProgram(t *testing.T) {
+	p := newRampBlender().Compile()
+	// two strips driven by one program, the second a step ahead of the first
+	first := NewRenderer(p, 1, nil)
+	second := NewRenderer(p, 1, nil)
+	second.AdvanceStep(1)
+	first.AdvanceStep(1)
+	second.AdvanceStep(1)
+	if first.Frame()[0].GetColor().R != 50 || second.Frame()[0].GetColor().R != 100 {
+		t.Errorf("Wanted %v and %v, got: %v and %v", 50, 100, first.Frame()[0].GetColor().R, second.Frame()[0].GetColor().R)
+	}
+}
@@ -0,0 +1,164 @@
+package transfunc
+
+import "math"
+
+// The noise functions are pure functions of their input and seed, so the same seed gives the same value at
+// the same step on every run. Use them as the function of any transition func, with an input range covering
+// the number of noise features wanted over the period, e.g. []float32{0, 20} for 20 flickers.
+
+// hash mixes the seed and a lattice position into a uniformly distributed value, using the splitmix64 finalizer
+func hash(seed int64, i int64) uint64 {
+	z := uint64(seed) + uint64(i)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// random returns a value in the range [0, 1) for the seed and lattice position
+func random(seed int64, i int64) float32 {
+	return float32(hash(seed, i)>>40) / (1 << 24)
+}
+
+// fade is the quintic curve that smooths noise between lattice positions
+func fade(t float32) float32 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// clamp01 keeps a value in the range [0, 1]
+func clamp01(v float32) float32 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// PerlinNoise returns 1D gradient noise in the range [0, 1]. It is 0.5 at every whole number input
+// and changes smoothly between them, with about one rise or fall per unit of input.
+func PerlinNoise(seed int64) func(x float32) float32 {
+	return func(x float32) float32 {
+		cell := math.Floor(float64(x))
+		i := int64(cell)
+		t := x - float32(cell)
+		// each lattice position gets a gradient in the range [-1, 1]
+		g0 := 2*random(seed, i) - 1
+		g1 := 2*random(seed, i+1) - 1
+		v0 := g0 * t
+		v1 := g1 * (t - 1)
+		// the result is within [-0.5, 0.5]
+		return clamp01(0.5 + v0 + fade(t)*(v1-v0))
+	}
+}
+
+// ValueNoise returns 1D value noise in the range [0, 1], a random value at each whole number input
+// smoothly interpolated between them
+func ValueNoise(seed int64) func(x float32) float32 {
+	return func(x float32) float32 {
+		cell := math.Floor(float64(x))
+		i := int64(cell)
+		t := x - float32(cell)
+		v0, v1 := random(seed, i), random(seed, i+1)
+		return v0 + fade(t)*(v1-v0)
+	}
+}
+
+// FractalNoise adds octaves of a noise function, each twice the frequency and half the amplitude
+// of the one before, for more natural detail such as fire. The result stays in the range [0, 1].
+func FractalNoise(noise func(x float32) float32, octaves int) func(x float32) float32 {
+	if octaves < 1 {
+		octaves = 1
+	}
+	return func(x float32) float32 {
+		var sum, total float32
+		frequency, amplitude := float32(1), float32(1)
+		for o := 0; o < octaves; o++ {
+			sum += amplitude * noise(x*frequency+float32(o)*17.31)
+			total += amplitude
+			frequency *= 2
+			amplitude /= 2
+		}
+		return sum / total
+	}
+}
+
+// RandomWalk returns a walk that starts at 0.5 and moves up or down by up to stepSize at each whole number
+// input, bouncing off 0 and 1, and interpolates linearly between them. The walk is calculated up front for
+// the given number of steps and repeats after that.
+func RandomWalk(seed int64, steps int, stepSize float32) func(x float32) float32 {
+	if steps < 1 {
+		steps = 1
+	}
+	walk := make([]float32, steps+1)
+	walk[0] = 0.5
+	for i := 1; i <= steps; i++ {
+		v := walk[i-1] + stepSize*(2*random(seed, int64(i))-1)
+		// reflect off the limits
+		if v < 0 {
+			v = -v
+		}
+		if v > 1 {
+			v = 2 - v
+		}
+		walk[i] = clamp01(v)
+	}
+	return func(x float32) float32 {
+		// wrap the input into the walk
+		pos := math.Mod(float64(x), float64(steps))
+		if pos < 0 {
+			pos += float64(steps)
+		}
+		i := int(pos)
+		t := float32(pos) - float32(i)
+		return walk[i] + t*(walk[i+1]-walk[i])
+	}
+}
+
+// sparkleCutoff is the level below which a fading sparkle is dropped, it is less than half of one uint8 step
+const sparkleCutoff = 1.0 / 512
+
+// sparkleMaxLookback is the most cells before the current one searched for sparkles that are still fading
+const sparkleMaxLookback = 64
+
+// Sparkle returns a twinkle generator that is usually 0. Each whole number input has a chance of density
+// to start a sparkle at a random point within it, which jumps to a random brightness between 0.5 and 1
+// and then fades away at the decay rate per unit of input. Sparkles fade out smoothly unless the decay
+// is so slow they last longer than 64 units. The density is clamped to [0, 1] and a negative decay is
+// treated as zero, so the result stays in the range [0, 1].
+func Sparkle(seed int64, density float32, decay float32) func(x float32) float32 {
+	density = clamp01(density)
+	if decay < 0 {
+		decay = 0
+	}
+	// look back far enough for a full brightness sparkle to fade below the cutoff
+	lookback := int64(sparkleMaxLookback)
+	if decay > 0 {
+		cells := math.Ceil(math.Log(1/sparkleCutoff)/float64(decay)) + 1
+		if cells < sparkleMaxLookback {
+			lookback = int64(cells)
+		}
+	}
+	return func(x float32) float32 {
+		cell := int64(math.Floor(float64(x)))
+		var result float32
+		// sparkles from the cells before can still be fading
+		for i := cell - lookback; i <= cell; i++ {
+			h := hash(seed, i)
+			if float32(h>>40)/(1<<24) >= density {
+				continue
+			}
+			// use other bits of the hash for the start and brightness
+			start := float32(i) + float32((h>>16)&0xffff)/(1<<16)
+			if x < start {
+				continue
+			}
+			peak := 0.5 + 0.5*float32(h&0xffff)/(1<<16)
+			v := peak * float32(math.Exp(float64(-decay*(x-start))))
+			if v > result {
+				result = v
+			}
+		}
+		return clamp01(result)
+	}
+}
@@ -0,0 +1,172 @@
+package transfunc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNoiseDeterministic(t *testing.T) {
+	tests := []struct {
+		name string
+		make func(seed int64) func(x float32) float32
+	}{
+		{"perlin", PerlinNoise},
+		{"value", ValueNoise},
+		{"fractal", func(seed int64) func(x float32) float32 { return FractalNoise(PerlinNoise(seed), 4) }},
+		{"walk", func(seed int64) func(x float32) float32 { return RandomWalk(seed, 50, 0.2) }},
+		{"sparkle", func(seed int64) func(x float32) float32 { return Sparkle(seed, 0.5, 2) }},
+	}
+
+	for _, test := range tests {
+		a, b, c := test.make(42), test.make(42), test.make(43)
+		differs := false
+		for i := -200; i < 2000; i++ {
+			x := float32(i) * 0.037
+			va, vb := a(x), b(x)
+			if va != vb {
+				t.Errorf("%s: Wanted %v, got: %v", test.name, va, vb)
+			}
+			if va < 0 || va > 1 {
+				t.Errorf("%s: value out of range at %v: %v", test.name, x, va)
+			}
+			if c(x) != va {
+				differs = true
+			}
+		}
+		if !differs {
+			t.Errorf("%s: different seeds gave the same noise", test.name)
+		}
+	}
+}
+
+func TestPerlinNoise(t *testing.T) {
+	f := PerlinNoise(7)
+	for i := -5; i < 20; i++ {
+		if result := f(float32(i)); result != 0.5 {
+			t.Errorf("Wanted %v, got: %v", 0.5, result)
+		}
+	}
+	// small changes in input give small changes in output
+	for i := 0; i < 1000; i++ {
+		x := float32(i) * 0.01
+		if d := math.Abs(float64(f(x+0.001) - f(x))); d > 0.01 {
+			t.Errorf("Noise jumped by %v at %v", d, x)
+		}
+	}
+}
+
+func TestRandomWalk(t *testing.T) {
+	f := RandomWalk(3, 100, 0.1)
+	if result := f(0); result != 0.5 {
+		t.Errorf("Wanted %v, got: %v", 0.5, result)
+	}
+	for i := 0; i+1 < 100; i++ {
+		if d := math.Abs(float64(f(float32(i+1)) - f(float32(i)))); d > 0.1+1e-6 {
+			t.Errorf("Walk moved by %v at %v", d, i)
+		}
+	}
+	// the walk starts again after the last step
+	if f(12.5) != f(112.5) || f(12.5) != f(-87.5) {
+		t.Errorf("Walk did not repeat: %v, %v, %v", f(12.5), f(112.5), f(-87.5))
+	}
+}
+
+func TestSparkle(t *testing.T) {
+	tests := []struct {
+		density float32
+	}{
+		{0},
+		{0.1},
+		{0.5},
+		{1},
+	}
+
+	for _, test := range tests {
+		f := Sparkle(11, test.density, 50)
+		// with a fast decay each sparkle is over well within its cell, so count the lit cells
+		lit := 0
+		cells := 2000
+		for i := 0; i < cells; i++ {
+			for j := 0; j < 32; j++ {
+				if f(float32(i)+float32(j)/32) > 0.05 {
+					lit++
+					break
+				}
+			}
+		}
+		got := float32(lit) / float32(cells)
+		if math.Abs(float64(got-test.density)) > 0.05 {
+			t.Errorf("Wanted %v, got: %v", test.density, got)
+		}
+	}
+}
+
+func TestNoiseFuncSlice(t *testing.T) {
+	build := func(seed int64) BrightnessFuncSlice {
+		var s BrightnessFuncSlice
+		noise := NewBrightnessFunc(PerlinNoise(seed), 100, []float32{0, 10})
+		s.AppendFunc(&noise)
+		sparkle := NewBrightnessFunc(Sparkle(seed, 0.3, 4), 100, []float32{0, 10})
+		s.AppendFunc(&sparkle)
+		return s
+	}
+	a, b := build(5), build(5)
+	for step := 0; step < 400; step++ {
+		va, _ := a.GetFuncValue(step)
+		vb, _ := b.GetFuncValue(step)
+		if va != vb {
+			t.Errorf("Wanted %v, got: %v", va, vb)
+		}
+	}
+}
+
+func TestSparkleFadesSmoothly(t *testing.T) {
+	tests := []struct {
+		decay float32
+	}{
+		{0.2},
+		{1},
+		{50},
+	}
+
+	for _, test := range tests {
+		f := Sparkle(9, 0.3, test.decay)
+		last := f(0)
+		for i := 1; i < 100000; i++ {
+			v := f(float32(i) * 0.002)
+			// a new sparkle can jump up, but a fading one only falls at the decay rate
+			if drop := last - v; drop > 0.002*test.decay+sparkleCutoff {
+				t.Errorf("Decay %v: dropped by %v at %v", test.decay, drop, float32(i)*0.002)
+				break
+			}
+			last = v
+		}
+	}
+}
+
+func TestSparkleLimits(t *testing.T) {
+	tests := []struct {
+		density float32
+		decay   float32
+	}{
+		{1, -1},
+		{2, -5},
+		{-1, 1},
+		{1, 0},
+	}
+
+	for _, test := range tests {
+		f := Sparkle(1, test.density, test.decay)
+		for i := 0; i < 2000; i++ {
+			x := float32(i) * 0.05
+			if v := f(x); v < 0 || v > 1 {
+				t.Errorf("Density %v decay %v: value out of range at %v: %v", test.density, test.decay, x, v)
+				break
+			}
+		}
+	}
+	// a negative decay holds the sparkle like a decay of zero
+	if a, b := Sparkle(1, 1, -1)(10), Sparkle(1, 1, 0)(10); a != b {
+		t.Errorf("Wanted %v, got: %v", b, a)
+	}
+}
@@ -0,0 +1,27 @@
+package transfunc
+
+// CommonPeriod returns the least common multiple of the positive periods, the number of steps after which
+// funcs with these periods all line up again. Periods of zero or less are ignored, if there are none the
+// result is zero.
+func CommonPeriod(periods ...int) int {
+	period := 0
+	for _, p := range periods {
+		if p <= 0 {
+			continue
+		}
+		if period == 0 {
+			period = p
+			continue
+		}
+		period = period / gcd(period, p) * p
+	}
+	return period
+}
+
+// gcd returns the greatest common divisor of two positive integers
+func gcd(a int, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,26 @@
+package transfunc
+
+import "testing"
+
+func TestCommonPeriod(t *testing.T) {
+	tests := []struct {
+		periods []int
+		want    int
+	}{
+		{nil, 0},
+		{[]int{0, 0, 0}, 0},
+		{[]int{-3, 0}, 0},
+		{[]int{0, 5, 0}, 5},
+		{[]int{1, 2, 3}, 6},
+		{[]int{4, 6}, 12},
+		{[]int{6, 4, 0, -2}, 12},
+		{[]int{1440, 1440, 1440}, 1440},
+		{[]int{7, 13}, 91},
+	}
+
+	for _, test := range tests {
+		if result := CommonPeriod(test.periods...); result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
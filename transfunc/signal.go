@@ -0,0 +1,41 @@
+package transfunc
+
+import "image/color"
+
+// Signal is an external feed, such as an audio level, with a value in the range [0, 1]
+type Signal interface {
+	Value() float32
+}
+
+// signalFunction returns a transition function that ignores its input and reads the signal instead
+func signalFunction(s Signal) func(x float32) float32 {
+	return func(x float32) float32 {
+		v := s.Value()
+		// keep the value in range so it can't overflow the uint8 conversions
+		if v < 0 {
+			return 0
+		}
+		if v > 1 {
+			return 1
+		}
+		return v
+	}
+}
+
+// NewSignalBrightnessFunc creates a new BrightnessFunc driven by the signal instead of the step.
+// The period is only used to place the func within its slice.
+func NewSignalBrightnessFunc(s Signal, period int) BrightnessFunc {
+	return NewBrightnessFunc(signalFunction(s), period, nil)
+}
+
+// NewSignalWhiteLevelFunc creates a new WhiteLevelFunc driven by the signal instead of the step.
+// The period is only used to place the func within its slice.
+func NewSignalWhiteLevelFunc(s Signal, period int) WhiteLevelFunc {
+	return NewWhiteLevelFunc(signalFunction(s), period, nil)
+}
+
+// NewSignalColorFunc creates a new ColorFunc that transitions from color1 to color2 as the signal rises.
+// The period is only used to place the func within its slice.
+func NewSignalColorFunc(color1 color.RGBA, color2 color.RGBA, transType TransType, s Signal, period int) ColorFunc {
+	return NewColorFunc(color1, color2, transType, signalFunction(s), period, nil)
+}
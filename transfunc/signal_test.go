@@ -0,0 +1,40 @@
+package transfunc
+
+import (
+	"image/color"
+	"testing"
+)
+
+type fixedSignal float32
+
+func (s fixedSignal) Value() float32 {
+	return float32(s)
+}
+
+func TestSignalFuncs(t *testing.T) {
+	tests := []struct {
+		signal fixedSignal
+		want   float32
+	}{
+		{0.5, 0.5},
+		{-1, 0},
+		{2, 1},
+	}
+
+	for _, test := range tests {
+		bf := NewSignalBrightnessFunc(test.signal, 3)
+		wf := NewSignalWhiteLevelFunc(test.signal, 3)
+		cf := NewSignalColorFunc(color.RGBA{R: 255}, color.RGBA{B: 255}, AllAtOnce, test.signal, 3)
+		// the step makes no difference
+		for step := 0; step < 3; step++ {
+			for _, f := range []transFuncer{&bf, &wf, &cf} {
+				if result := f.GetFuncValue(step); result != test.want {
+					t.Errorf("Wanted %v, got: %v", test.want, result)
+				}
+			}
+		}
+		if bf.GetFuncPeriod() != 3 || cf.Color2.B != 255 {
+			t.Errorf("Unexpected func: %v, %v", bf.GetFuncPeriod(), cf.Color2)
+		}
+	}
+}
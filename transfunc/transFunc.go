@@ -1,8 +1,20 @@
 package transfunc
 
+import "errors"
+
+var (
+	// ErrNilFunction is returned when a transition function has no Function
+	ErrNilFunction = errors.New("function is nil")
+	// ErrInvalidPeriod is returned when a transition function's period is less than one
+	ErrInvalidPeriod = errors.New("period must be greater than zero")
+	// ErrInvalidTransType is returned when a ColorFunc has an unknown TransType
+	ErrInvalidTransType = errors.New("invalid color transition type")
+)
+
 type transFuncer interface {
 	GetFuncValue(stepNum int) float32
 	GetFuncPeriod() int
+	Validate() error
 }
 
 type transFunc struct {
@@ -15,16 +27,28 @@ func (f *transFunc) GetFuncPeriod() int {
 	return f.Period
 }
 
+// Validate checks that the func can be evaluated without panicking
+func (f *transFunc) Validate() error {
+	if f.Function == nil {
+		return ErrNilFunction
+	}
+	if f.Period <= 0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
 func (f *transFunc) GetFuncValue(stepNum int) float32 {
-	// make sure the input range is valid
-	if len(f.InputRange) != 2 {
-		// set to zero value
-		f.InputRange = []float32{0, 0}
+	// make sure the input range is valid without modifying the func, so it is safe for concurrent reads
+	inputRange := f.InputRange
+	if len(inputRange) != 2 {
+		// use the zero value
+		inputRange = []float32{0, 0}
 	}
 	// find the input value
 	stepMin := stepNum % f.Period
 	posInRange := float32(stepMin) / float32(f.Period)
-	inputValue := posInRange*(f.InputRange[1]-f.InputRange[0]) + f.InputRange[0]
+	inputValue := posInRange*(inputRange[1]-inputRange[0]) + inputRange[0]
 	// get the function value
 	return f.Function(inputValue)
 }
@@ -1,5 +1,7 @@
 package transfunc
 
+import "fmt"
+
 type transFuncSlice struct {
 	funcs  []transFuncer
 	period int
@@ -25,6 +27,13 @@ func (s *transFuncSlice) AppendFunc(f transFuncer) {
 	}
 }
 
+// clone returns a copy of the slice that is not affected by later changes to the original
+func (s *transFuncSlice) clone() transFuncSlice {
+	funcs := make([]transFuncer, len(s.funcs))
+	copy(funcs, s.funcs)
+	return transFuncSlice{funcs: funcs, period: s.period}
+}
+
 func (s *transFuncSlice) setPeriod() {
 	// get full period
 	var period int
@@ -37,15 +46,25 @@ func (s *transFuncSlice) setPeriod() {
 
 // GetFuncValue returns the value of the function at the given step
 func (s *transFuncSlice) GetFuncValue(stepNum int) (float32, transFuncer) {
-	if len(s.funcs) == 0 {
+	f, localStep := s.getFunc(stepNum)
+	if f == nil {
 		return 0, nil
 	}
+	// get the function value
+	return f.GetFuncValue(localStep), f
+}
+
+// getFunc returns the function active at the given step and the step within that function
+func (s *transFuncSlice) getFunc(stepNum int) (f transFuncer, localStep int) {
+	// a slice of zero period funcs can't be evaluated
+	if len(s.funcs) == 0 || s.period <= 0 {
+		return nil, 0
+	}
 	// mod the step number
 	minStep := stepNum % s.period
 	// find the function index
 	index, localStep := s.getFunctionIndex(minStep)
-	// get the function value
-	return s.funcs[index].GetFuncValue(localStep), s.funcs[index]
+	return s.funcs[index], localStep
 }
 
 func (s *transFuncSlice) getFunctionIndex(stepNum int) (index int, localStep int) {
@@ -64,6 +83,16 @@ func (s *transFuncSlice) getFunctionIndex(stepNum int) (index int, localStep int
 	return index, localStep
 }
 
+// Validate checks each func in the slice, returning the first error
+func (s *transFuncSlice) Validate() error {
+	for i := range s.funcs {
+		if err := s.funcs[i].Validate(); err != nil {
+			return fmt.Errorf("func %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
 func (s *transFuncSlice) GetPeriod() int {
 	return s.period
 }
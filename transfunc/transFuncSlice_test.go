@@ -1,6 +1,7 @@
 package transfunc
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -127,3 +128,23 @@ func TestGetPeriod(t *testing.T) {
 		t.Errorf("Wanted: %v, found: %v", period, result)
 	}
 }
+
+func TestSliceValidate(t *testing.T) {
+	s := transFuncSlice{}
+	if err := s.Validate(); err != nil {
+		t.Errorf("Wanted %v, got: %v", nil, err)
+	}
+	s.AppendFunc(&transFunc{Function: func(x float32) float32 { return x }, Period: 5})
+	s.AppendFunc(&transFunc{Period: 5})
+	if err := s.Validate(); !errors.Is(err, ErrNilFunction) {
+		t.Errorf("Wanted %v, got: %v", ErrNilFunction, err)
+	}
+}
+
+func TestGetFunctionValueZeroPeriod(t *testing.T) {
+	s := transFuncSlice{}
+	s.AppendFunc(&transFunc{Function: func(x float32) float32 { return x }})
+	if v, f := s.GetFuncValue(3); v != 0 || f != nil {
+		t.Errorf("Wanted %v, got: %v, %v", 0, v, f)
+	}
+}
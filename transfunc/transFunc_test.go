@@ -38,3 +38,23 @@ func TestGetFuncPeriod(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		function func(x float32) float32
+		period   int
+		want     error
+	}{
+		{func(x float32) float32 { return x }, 10, nil},
+		{nil, 10, ErrNilFunction},
+		{func(x float32) float32 { return x }, 0, ErrInvalidPeriod},
+		{func(x float32) float32 { return x }, -1, ErrInvalidPeriod},
+	}
+
+	for _, test := range tests {
+		f := transFunc{Function: test.function, Period: test.period}
+		if result := f.Validate(); result != test.want {
+			t.Errorf("Wanted %v, got: %v", test.want, result)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package transfunc
 
-import "image/color"
+import (
+	"fmt"
+	"image/color"
+)
 
 // BrightnessFunc stores a function that describes how to modify the brightness (alpha) of a Color
 type BrightnessFunc struct{ transFunc }
@@ -30,13 +33,22 @@ func (b *BrightnessFuncSlice) GetFuncValue(stepNum int) (uint8, bool) {
 	return uint8(0xff * funcVal), ok
 }
 
+// Clone returns a copy of the slice that is not affected by later changes to the original
+func (b *BrightnessFuncSlice) Clone() BrightnessFuncSlice {
+	return BrightnessFuncSlice{b.transFuncSlice.clone()}
+}
+
 // WhiteLevelFunc stores a function that describes how to modify the white level of a Color
-type WhiteLevelFunc struct{ transFunc }
+type WhiteLevelFunc struct {
+	// WhitePoint is the white the color is blended toward, the zero value is equal RGB white
+	WhitePoint color.RGBA
+	transFunc
+}
 
 // NewWhiteLevelFunc creates a new WhiteLevelFunc object
 func NewWhiteLevelFunc(f func(x float32) float32, period int, inputRange []float32) WhiteLevelFunc {
 	return WhiteLevelFunc{
-		transFunc{
+		transFunc: transFunc{
 			Function:   f,
 			Period:     period,
 			InputRange: inputRange,
@@ -44,6 +56,14 @@ func NewWhiteLevelFunc(f func(x float32) float32, period int, inputRange []float
 	}
 }
 
+// NewWhitePointFunc creates a new WhiteLevelFunc that blends toward the white point instead of equal RGB white,
+// such as a color temperature from color.Kelvin
+func NewWhitePointFunc(whitePoint color.RGBA, f func(x float32) float32, period int, inputRange []float32) WhiteLevelFunc {
+	wf := NewWhiteLevelFunc(f, period, inputRange)
+	wf.WhitePoint = whitePoint
+	return wf
+}
+
 // GetFuncValue returns the function value for the given step and the anchor colors
 func (w *WhiteLevelFuncSlice) GetFuncValue(stepNum int) (uint8, bool) {
 	funcVal, f := w.transFuncSlice.GetFuncValue(stepNum)
@@ -58,6 +78,22 @@ func (w *WhiteLevelFuncSlice) GetFuncValue(stepNum int) (uint8, bool) {
 // WhiteLevelFuncSlice holds a slice of WhiteLevelFuncs
 type WhiteLevelFuncSlice struct{ transFuncSlice }
 
+// GetWhitePoint returns the white point of the function at the given step, ok is false when there is
+// no function or it blends toward equal RGB white
+func (w *WhiteLevelFuncSlice) GetWhitePoint(stepNum int) (whitePoint color.RGBA, ok bool) {
+	f, _ := w.transFuncSlice.getFunc(stepNum)
+	wf, isWhiteLevelFunc := f.(*WhiteLevelFunc)
+	if !isWhiteLevelFunc || wf.WhitePoint == (color.RGBA{}) {
+		return color.RGBA{}, false
+	}
+	return wf.WhitePoint, true
+}
+
+// Clone returns a copy of the slice that is not affected by later changes to the original
+func (w *WhiteLevelFuncSlice) Clone() WhiteLevelFuncSlice {
+	return WhiteLevelFuncSlice{w.transFuncSlice.clone()}
+}
+
 // ColorFunc stores a function that describes the transition from one Color to another
 type ColorFunc struct {
 	Color1    color.RGBA
@@ -81,16 +117,30 @@ func NewColorFunc(color1 color.RGBA, color2 color.RGBA, transType TransType, f f
 	}
 }
 
+// Validate checks that the func can be evaluated without panicking
+func (c *ColorFunc) Validate() error {
+	if !c.TransType.isValid() {
+		return fmt.Errorf("%w: %v", ErrInvalidTransType, c.TransType)
+	}
+	return c.transFunc.Validate()
+}
+
 // ColorFuncSlice holds a slice of ColorFuncs
 type ColorFuncSlice struct{ transFuncSlice }
 
 // GetFuncValue returns the function value for the given step and the anchor colors
 func (c *ColorFuncSlice) GetFuncValue(stepNum int) (float32, *ColorFunc) {
 	funcVal, tf := c.transFuncSlice.GetFuncValue(stepNum)
-	cf := tf.(*ColorFunc)
+	// the func is nil when the slice is empty
+	cf, _ := tf.(*ColorFunc)
 	return funcVal, cf
 }
 
+// Clone returns a copy of the slice that is not affected by later changes to the original
+func (c *ColorFuncSlice) Clone() ColorFuncSlice {
+	return ColorFuncSlice{c.transFuncSlice.clone()}
+}
+
 // TransType defines the type of transition
 type TransType int
 
@@ -103,8 +153,22 @@ const (
 	ToWhite
 	// ToBlack tansitions from color1 to black to color2
 	ToBlack
+	// Decomposed changes the base color one component at a time while the white level and value change
+	// directly, so the hue, whiteness and value of the color each move independently
+	Decomposed
+	// Temperature moves along the blackbody locus between the color temperatures of color1 and color2,
+	// evenly in mireds, while the value changes directly. It is meant for whites such as those from color.Kelvin.
+	Temperature
 )
 
 func (t TransType) String() string {
-	return [...]string{"OneAtATime", "AllAtOnce", "ToWhite", "ToBlack"}[t]
+	if !t.isValid() {
+		return fmt.Sprintf("TransType(%d)", int(t))
+	}
+	return [...]string{"OneAtATime", "AllAtOnce", "ToWhite", "ToBlack", "Decomposed", "Temperature"}[t]
+}
+
+// isValid reports whether the TransType is one of the defined transition types
+func (t TransType) isValid() bool {
+	return t >= OneAtATime && t <= Temperature
 }
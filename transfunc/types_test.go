@@ -1,6 +1,7 @@
 package transfunc
 
 import (
+	"errors"
 	imageColor "image/color"
 	"testing"
 )
@@ -51,6 +52,33 @@ func TestWhiteLevelGetFuncValue(t *testing.T) {
 	}
 }
 
+func TestWhiteLevelGetWhitePoint(t *testing.T) {
+	f := func(x float32) float32 { return x }
+	warm := imageColor.RGBA{R: 255, G: 173, B: 89}
+	s := &WhiteLevelFuncSlice{}
+	if _, ok := s.GetWhitePoint(0); ok {
+		t.Errorf("Wanted no white point for an empty slice")
+	}
+	wf := NewWhiteLevelFunc(f, 2, nil)
+	s.AppendFunc(&wf)
+	wpf := NewWhitePointFunc(warm, f, 2, nil)
+	s.AppendFunc(&wpf)
+	tests := []struct {
+		step   int
+		want   imageColor.RGBA
+		wantOk bool
+	}{
+		{1, imageColor.RGBA{}, false},
+		{2, warm, true},
+		{7, warm, true},
+	}
+	for _, test := range tests {
+		if result, ok := s.GetWhitePoint(test.step); result != test.want || ok != test.wantOk {
+			t.Errorf("Wanted %v %v, got: %v %v", test.want, test.wantOk, result, ok)
+		}
+	}
+}
+
 func TestColorGetFuncValue(t *testing.T) {
 	tests := []struct {
 		funcVal   float32
@@ -96,16 +124,20 @@ func TestColorGetFuncValue(t *testing.T) {
 
 func TestTransTypeString(t *testing.T) {
 	tests := map[string]TransType{
-		"OneAtATime": OneAtATime,
-		"AllAtOnce":  AllAtOnce,
-		"ToWhite":    ToWhite,
-		"ToBlack":    ToBlack,
+		"OneAtATime":  OneAtATime,
+		"AllAtOnce":   AllAtOnce,
+		"ToWhite":     ToWhite,
+		"ToBlack":     ToBlack,
+		"Decomposed":  Decomposed,
+		"Temperature": Temperature,
 	}
 	want := []string{
 		"OneAtATime",
 		"AllAtOnce",
 		"ToWhite",
 		"ToBlack",
+		"Decomposed",
+		"Temperature",
 	}
 	for index := range want {
 		w := want[index]
@@ -115,3 +147,59 @@ func TestTransTypeString(t *testing.T) {
 		}
 	}
 }
+
+func TestTransTypeStringOutOfRange(t *testing.T) {
+	tests := map[TransType]string{
+		TransType(-1): "TransType(-1)",
+		TransType(6):  "TransType(6)",
+	}
+	for tt, want := range tests {
+		if got := tt.String(); got != want {
+			t.Errorf("Wanted %v, got: %v", want, got)
+		}
+	}
+}
+
+func TestColorFuncValidate(t *testing.T) {
+	f := func(x float32) float32 { return x }
+	tests := []struct {
+		cf   ColorFunc
+		want error
+	}{
+		{NewColorFunc(imageColor.RGBA{}, imageColor.RGBA{}, AllAtOnce, f, 5, nil), nil},
+		{NewColorFunc(imageColor.RGBA{}, imageColor.RGBA{}, TransType(9), f, 5, nil), ErrInvalidTransType},
+		{NewColorFunc(imageColor.RGBA{}, imageColor.RGBA{}, AllAtOnce, f, 0, nil), ErrInvalidPeriod},
+	}
+	for _, test := range tests {
+		if err := test.cf.Validate(); !errors.Is(err, test.want) {
+			t.Errorf("Wanted %v, got: %v", test.want, err)
+		}
+	}
+}
+
+func TestColorGetFuncValueEmpty(t *testing.T) {
+	s := ColorFuncSlice{}
+	if _, cf := s.GetFuncValue(0); cf != nil {
+		t.Errorf("Wanted %v, got: %v", nil, cf)
+	}
+}
+
+func TestClone(t *testing.T) {
+	s := &BrightnessFuncSlice{}
+	s.AppendFunc(&transFunc{Period: 2, Function: func(x float32) float32 { return 1 }})
+	clone := s.Clone()
+	s.AppendFunc(&transFunc{Period: 3, Function: func(x float32) float32 { return 0 }})
+	if clone.GetPeriod() != 2 || len(clone.funcs) != 1 {
+		t.Errorf("Wanted: %v, found: %v", 2, clone.GetPeriod())
+	}
+	w := &WhiteLevelFuncSlice{}
+	w.AppendFunc(&transFunc{Period: 4})
+	if wc := w.Clone(); wc.GetPeriod() != 4 {
+		t.Errorf("Wanted: %v, found: %v", 4, wc.GetPeriod())
+	}
+	c := &ColorFuncSlice{}
+	c.AppendFunc(&ColorFunc{transFunc: transFunc{Period: 5}})
+	if cc := c.Clone(); cc.GetPeriod() != 5 {
+		t.Errorf("Wanted: %v, found: %v", 5, cc.GetPeriod())
+	}
+}